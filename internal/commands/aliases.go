@@ -15,21 +15,25 @@ import (
 var aliasesCmd = &cobra.Command{
 	Use:   "aliases",
 	Short: "Creates aliases to all the installed apps",
-	Long:  ``,
+	Long: `Creates aliases to all the installed apps. The aliases are kept in a file per shell under the
+cli-manager folder, rewritten whenever apps are installed or removed, so starting a shell only sources it.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		gen, _ := cmd.Flags().GetBool("generate")
 		install, _ := cmd.Flags().GetBool("install")
+		uninstall, _ := cmd.Flags().GetBool("uninstall")
 		shellType := getShellType(cmd)
 
 		switch shellType {
 		case Zsh:
-			handleZshAliases(gen, install)
+			handleZshAliases(gen, install, uninstall)
 		case Bash:
-			handleBashAliases(gen, install)
+			handleBashAliases(gen, install, uninstall)
 		case Powershell:
-			handlePowershellAliases(gen, install, false)
+			handlePowershellAliases(gen, install, uninstall, false)
 		case PowershellCore:
-			handlePowershellAliases(gen, install, true)
+			handlePowershellAliases(gen, install, uninstall, true)
+		case Fish:
+			handleFishAliases(gen, install, uninstall)
 		case Unknown:
 			fmt.Println("Unknown shell, please specify your shell using flags")
 			os.Exit(1)
@@ -37,92 +41,118 @@ var aliasesCmd = &cobra.Command{
 	},
 }
 
-const zshAliasesSnippet = "\nsource <(cli-manager aliases -g -z)\n"
-const bashAliasesSnippet = "\nsource <(cli-manager aliases -g -b)\n"
-const powershellAliasesSnippet = "\nInvoke-Expression $($(cli-manager.exe aliases -g -p) -join \"`n\")\n"
+const zshAliasesSnippet = "\n[ -f ~/.cli-manager/aliases/aliases.zsh ] && source ~/.cli-manager/aliases/aliases.zsh\n"
+const bashAliasesSnippet = "\n[ -f ~/.cli-manager/aliases/aliases.bash ] && source ~/.cli-manager/aliases/aliases.bash\n"
+const powershellAliasesSnippet = "\nif (Test-Path \"$HOME/.cli-manager/aliases/aliases.ps1\") { . \"$HOME/.cli-manager/aliases/aliases.ps1\" }\n"
+const fishAliasesSnippet = "\ntest -f ~/.cli-manager/aliases/aliases.fish; and source ~/.cli-manager/aliases/aliases.fish\n"
+const fishAliasesFile = "cli-manager-aliases.fish"
 
-func handleZshAliases(generate bool, install bool) {
+// snippets installed by earlier versions, which ran cli-manager every time a shell started
+const legacyZshAliasesSnippet = "\nsource <(cli-manager aliases -g -z)\n"
+const legacyBashAliasesSnippet = "\nsource <(cli-manager aliases -g -b)\n"
+const legacyPowershellAliasesSnippet = "\nInvoke-Expression $($(cli-manager.exe aliases -g -p) -join \"`n\")\n"
+
+func handleZshAliases(generate bool, install bool, uninstall bool) {
 	switch {
 	case generate:
 		manager := nodeman.NewManager(afero.NewOsFs())
-		apps := manager.GetInstalledExecutables()
-		for _, app := range apps {
-			fmt.Printf("alias %s='cli-manager run %s'\n", app, app)
-		}
-	case install:
+		fmt.Print(manager.AliasesScript(nodeman.ZshAliasesFile))
+	case install, uninstall:
 		dir, err := os.UserHomeDir()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
 		scriptPath := filepath.Join(dir, ".zshrc")
-		wrote, err := writeShellSnippet(zshAliasesSnippet, scriptPath)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		if wrote {
-			fmt.Printf("Wrote aliases script to: %s\n", scriptPath)
-		} else {
-			fmt.Printf("Aliases already installed in: %s\n", scriptPath)
+		if uninstall {
+			uninstallShellSnippets("aliases script", scriptPath, zshAliasesSnippet, legacyZshAliasesSnippet)
+			return
 		}
+		installAliasesSnippet(zshAliasesSnippet, scriptPath, legacyZshAliasesSnippet)
 	default:
 		fmt.Printf("Add the following line to your .zshrc file:\n\n%s", zshAliasesSnippet)
 	}
 }
 
-func handleBashAliases(generate bool, install bool) {
+func handleBashAliases(generate bool, install bool, uninstall bool) {
 	switch {
 	case generate:
 		manager := nodeman.NewManager(afero.NewOsFs())
-		apps := manager.GetInstalledExecutables()
-		for _, app := range apps {
-			fmt.Printf("alias %s='cli-manager run %s'\n", app, app)
-		}
-	case install:
+		fmt.Print(manager.AliasesScript(nodeman.BashAliasesFile))
+	case install, uninstall:
 		dir, err := os.UserHomeDir()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
 		scriptPath := filepath.Join(dir, ".bashrc")
-		wrote, err := writeShellSnippet(bashAliasesSnippet, scriptPath)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		if wrote {
-			fmt.Printf("Wrote aliases script to: %s\n", scriptPath)
-		} else {
-			fmt.Printf("Aliases already installed in: %s\n", scriptPath)
+		if uninstall {
+			uninstallShellSnippets("aliases script", scriptPath, bashAliasesSnippet, legacyBashAliasesSnippet)
+			return
 		}
+		installAliasesSnippet(bashAliasesSnippet, scriptPath, legacyBashAliasesSnippet)
 	default:
 		fmt.Printf("Add the following line to your .bashrc or .profile file:\n\n%s", bashAliasesSnippet)
 	}
 }
 
-func handlePowershellAliases(generate bool, install bool, core bool) {
+func handlePowershellAliases(generate bool, install bool, uninstall bool, core bool) {
 	switch {
 	case generate:
 		manager := nodeman.NewManager(afero.NewOsFs())
-		apps := manager.GetInstalledExecutables()
-		for _, app := range apps {
-			fmt.Printf("function %s { cli-manager.exe run %s @args }", app, app)
-		}
+		fmt.Print(manager.AliasesScript(nodeman.PowershellAliasesFile))
+	case uninstall:
+		uninstallShellSnippets("aliases script", getPowershellProfilePath(core),
+			powershellAliasesSnippet, legacyPowershellAliasesSnippet)
 	case install:
-		scriptPath := getPowershellProfilePath(core)
-		wrote, err := writeShellSnippet(powershellAliasesSnippet, scriptPath)
+		installAliasesSnippet(powershellAliasesSnippet, getPowershellProfilePath(core), legacyPowershellAliasesSnippet)
+	default:
+		fmt.Printf("Add the following line to your $PROFILE file:\n\n%s", powershellAliasesSnippet)
+	}
+}
+
+func handleFishAliases(generate bool, install bool, uninstall bool) {
+	switch {
+	case generate:
+		manager := nodeman.NewManager(afero.NewOsFs())
+		fmt.Print(manager.AliasesScript(nodeman.FishAliasesFile))
+	case install, uninstall:
+		scriptPath, err := getFishConfigPath(fishAliasesFile)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		if wrote {
-			fmt.Printf("Wrote aliases script to: %s\n", scriptPath)
-		} else {
-			fmt.Printf("Aliases already installed in: %s\n", scriptPath)
+		if uninstall {
+			uninstallShellSnippets("aliases script", scriptPath, fishAliasesSnippet)
+			return
 		}
+		installAliasesSnippet(fishAliasesSnippet, scriptPath)
 	default:
-		fmt.Printf("Add the following line to your $PROFILE file:\n\n%s", powershellAliasesSnippet)
+		fmt.Printf("Add the following line to your config.fish file:\n\n%s", fishAliasesSnippet)
+	}
+}
+
+// installAliasesSnippet writes the aliases files and makes the profile source them,
+// replacing the snippet of earlier versions
+func installAliasesSnippet(snippet string, scriptPath string, legacySnippets ...string) {
+	manager := nodeman.NewManager(afero.NewOsFs())
+	err := manager.WriteAliasesFiles()
+	if err == nil {
+		_, err = removeShellSnippets(scriptPath, legacySnippets...)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	wrote, err := writeShellSnippet(snippet, scriptPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if wrote {
+		fmt.Printf("Wrote aliases script to: %s\n", scriptPath)
+	} else {
+		fmt.Printf("Aliases already installed in: %s\n", scriptPath)
 	}
 }
 
@@ -134,5 +164,7 @@ func init() {
 	aliasesCmd.Flags().BoolP("pwsh", "c", false, "Generate powershell core aliases")
 	aliasesCmd.Flags().BoolP("bash", "b", false, "Generate bash aliases")
 	aliasesCmd.Flags().BoolP("zsh", "z", false, "Generate zsh aliases")
+	aliasesCmd.Flags().BoolP("fish", "f", false, "Generate fish aliases")
 	aliasesCmd.Flags().BoolP("install", "i", false, "Install the aliases init to the default location.")
+	aliasesCmd.Flags().Bool("uninstall", false, "Remove the aliases init from the default location.")
 }
@@ -10,6 +10,9 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/rdaniels6813/cli-manager/internal/util"
+	"github.com/spf13/afero"
+
 	"github.com/spf13/cobra"
 )
 
@@ -21,17 +24,20 @@ var completionCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		gen, _ := cmd.Flags().GetBool("generate")
 		install, _ := cmd.Flags().GetBool("install")
+		uninstall, _ := cmd.Flags().GetBool("uninstall")
 		shellType := getShellType(cmd)
 
 		switch shellType {
 		case Zsh:
-			handleZshCompletion(gen, install)
+			handleZshCompletion(gen, install, uninstall)
 		case Bash:
-			handleBashCompletion(gen, install)
+			handleBashCompletion(gen, install, uninstall)
 		case Powershell:
-			handlePowershellCompletion(gen, install, false)
+			handlePowershellCompletion(gen, install, uninstall, false)
 		case PowershellCore:
-			handlePowershellCompletion(gen, install, true)
+			handlePowershellCompletion(gen, install, uninstall, true)
+		case Fish:
+			handleFishCompletion(gen, install, uninstall)
 		case Unknown:
 			fmt.Println("Unknown shell, please specify your shell using flags")
 			os.Exit(1)
@@ -42,8 +48,10 @@ var completionCmd = &cobra.Command{
 const zshCompletionSnippet = "\nsource <(cli-manager completion -g -z)\n"
 const bashCompletionSnippet = "\nsource <(cli-manager completion -g -b)\n"
 const powershellCompletionSnippet = "\nInvoke-Expression $($(cli-manager.exe completion -g -p) -join \"`n\")\n"
+const fishCompletionSnippet = "\ncli-manager completion -g -f | source\n"
+const fishCompletionFile = "cli-manager-completion.fish"
 
-func handleZshCompletion(generate bool, install bool) {
+func handleZshCompletion(generate bool, install bool, uninstall bool) {
 	switch {
 	case generate:
 		var data []byte
@@ -54,6 +62,13 @@ func handleZshCompletion(generate bool, install bool) {
 		}
 		output, _ := io.ReadAll(buf)
 		fmt.Print(strings.TrimPrefix(string(output), "#"))
+	case uninstall:
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		uninstallShellSnippets("completion script", filepath.Join(dir, ".zshrc"), zshCompletionSnippet)
 	case install:
 		dir, err := os.UserHomeDir()
 		if err != nil {
@@ -76,13 +91,20 @@ func handleZshCompletion(generate bool, install bool) {
 	}
 }
 
-func handleBashCompletion(generate bool, install bool) {
+func handleBashCompletion(generate bool, install bool, uninstall bool) {
 	switch {
 	case generate:
 		err := rootCmd.GenBashCompletion(os.Stdout)
 		if err != nil {
 			fmt.Println(err)
 		}
+	case uninstall:
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		uninstallShellSnippets("completion script", filepath.Join(dir, ".bashrc"), bashCompletionSnippet)
 	case install:
 		dir, err := os.UserHomeDir()
 		if err != nil {
@@ -105,13 +127,15 @@ func handleBashCompletion(generate bool, install bool) {
 	}
 }
 
-func handlePowershellCompletion(generate bool, install bool, core bool) {
+func handlePowershellCompletion(generate bool, install bool, uninstall bool, core bool) {
 	switch {
 	case generate:
 		err := rootCmd.GenPowerShellCompletion(os.Stdout)
 		if err != nil {
 			fmt.Println(err)
 		}
+	case uninstall:
+		uninstallShellSnippets("completion script", getPowershellProfilePath(core), powershellCompletionSnippet)
 	case install:
 		scriptPath := getPowershellProfilePath(core)
 		wrote, err := writeShellSnippet(powershellCompletionSnippet, scriptPath)
@@ -129,6 +153,41 @@ func handlePowershellCompletion(generate bool, install bool, core bool) {
 	}
 }
 
+func handleFishCompletion(generate bool, install bool, uninstall bool) {
+	switch {
+	case generate:
+		err := rootCmd.GenFishCompletion(os.Stdout, true)
+		if err != nil {
+			fmt.Println(err)
+		}
+	case uninstall:
+		scriptPath, err := getFishConfigPath(fishCompletionFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		uninstallShellSnippets("completion script", scriptPath, fishCompletionSnippet)
+	case install:
+		scriptPath, err := getFishConfigPath(fishCompletionFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		wrote, err := writeShellSnippet(fishCompletionSnippet, scriptPath)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if wrote {
+			fmt.Printf("Wrote completion script to: %s\n", scriptPath)
+		} else {
+			fmt.Printf("Completion already installed in: %s\n", scriptPath)
+		}
+	default:
+		fmt.Printf("Add the following line to your config.fish file:\n\n%s", fishCompletionSnippet)
+	}
+}
+
 type shellType string
 
 const (
@@ -140,6 +199,8 @@ const (
 	Powershell shellType = "powershell"
 	// PowershellCore enum for powershell
 	PowershellCore shellType = "pwsh"
+	// Fish enum for the fish shell
+	Fish shellType = "fish"
 	// Unknown enum for unknown or unsupported shell
 	Unknown shellType = "unknown"
 )
@@ -149,6 +210,7 @@ func getShellType(cmd *cobra.Command) shellType {
 	powershell, _ := cmd.Flags().GetBool("powershell")
 	bash, _ := cmd.Flags().GetBool("bash")
 	powershellCore, _ := cmd.Flags().GetBool("pwsh")
+	fish, _ := cmd.Flags().GetBool("fish")
 	if zsh {
 		return Zsh
 	}
@@ -161,12 +223,18 @@ func getShellType(cmd *cobra.Command) shellType {
 	if powershellCore {
 		return PowershellCore
 	}
+	if fish {
+		return Fish
+	}
 	if os.Getenv("ZSH_NAME") != "" || os.Getenv("ZSH") != "" {
 		return Zsh
 	}
 	if os.Getenv("BASH") != "" {
 		return Bash
 	}
+	if os.Getenv("FISH_VERSION") != "" || filepath.Base(os.Getenv("SHELL")) == "fish" {
+		return Fish
+	}
 	psModule := os.Getenv("PSModulePath")
 	if psModule != "" {
 		powershellPartial := fmt.Sprintf("%spowershell%s", string(os.PathSeparator), string(os.PathSeparator))
@@ -202,6 +270,19 @@ func getPowershellProfilePath(core bool) string {
 	return filepath.Join(myDocuments, "PowerShell", "Microsoft.PowerShell_profile.ps1")
 }
 
+// getFishConfigPath returns the path of a file in fish's conf.d folder, which fish sources at startup
+func getFishConfigPath(name string) (string, error) {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		configDir = filepath.Join(dir, ".config")
+	}
+	return filepath.Join(configDir, "fish", "conf.d", name), nil
+}
+
 func writeShellSnippet(snippet string, path string) (bool, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		err := os.MkdirAll(filepath.Dir(path), 0777)
@@ -232,13 +313,35 @@ func writeShellSnippet(snippet string, path string) (bool, error) {
 	return true, err
 }
 
+// removeShellSnippets removes the snippets writeShellSnippet added to the file, leaving the rest of it as it was,
+// and deletes the files cli-manager created for itself once nothing is left in them. It reports whether the file changed.
+func removeShellSnippets(path string, snippets ...string) (bool, error) {
+	return util.RemoveSnippets(afero.NewOsFs(), path, strings.HasPrefix(filepath.Base(path), "cli-manager-"), snippets...)
+}
+
+// uninstallShellSnippets removes the snippets from the file, printing whether it changed
+func uninstallShellSnippets(name string, path string, snippets ...string) {
+	removed, err := removeShellSnippets(path, snippets...)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if removed {
+		fmt.Printf("Removed %s from: %s\n", name, path)
+	} else {
+		fmt.Printf("No %s in: %s\n", name, path)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(completionCmd)
 	completionCmd.Flags().BoolP("powershell", "p", false, "Generate powershell completion")
 	completionCmd.Flags().BoolP("pwsh", "c", false, "Generate powershell core completion")
 	completionCmd.Flags().BoolP("bash", "b", false, "Generate bash completion")
 	completionCmd.Flags().BoolP("zsh", "z", false, "Generate zsh completion")
+	completionCmd.Flags().BoolP("fish", "f", false, "Generate fish completion")
 	completionCmd.Flags().BoolP("generate", "g", false,
 		"Generate completion for shell specified by $SHELL and send to stdout")
 	completionCmd.Flags().BoolP("install", "i", false, "Install the completion script into the users profile")
+	completionCmd.Flags().Bool("uninstall", false, "Remove the completion script from the users profile")
 }
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+
+	"github.com/spf13/cobra"
+)
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Puts the shims of the installed apps on PATH",
+	Long: `Every installed bin gets an executable shim in the cli-manager bin folder, which runs it with the
+right node version. With the folder on PATH installed CLIs work like any other command, in scripts,
+Makefiles and cron jobs too.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		gen, _ := cmd.Flags().GetBool("generate")
+		install, _ := cmd.Flags().GetBool("install")
+		uninstall, _ := cmd.Flags().GetBool("uninstall")
+		shellType := getShellType(cmd)
+
+		switch shellType {
+		case Zsh:
+			handlePosixEnv(gen, install, uninstall, ".zshrc", zshEnvSnippet)
+		case Bash:
+			handlePosixEnv(gen, install, uninstall, ".bashrc", bashEnvSnippet)
+		case Powershell:
+			handlePowershellEnv(gen, install, uninstall, false)
+		case PowershellCore:
+			handlePowershellEnv(gen, install, uninstall, true)
+		case Fish:
+			handleFishEnv(gen, install, uninstall)
+		case Unknown:
+			shimFolder := nodeman.GetShimFolder(afero.NewOsFs())
+			fmt.Printf("Add the shim folder to PATH for your shell:\n\nzsh & bash:\n%s\n\nfish:\n%s\n\npowershell:\n%s\n",
+				posixPathSetup(shimFolder), fishPathSetup(shimFolder), powershellPathSetup(shimFolder))
+		}
+	},
+}
+
+const zshEnvSnippet = "\nexport PATH=\"$HOME/.cli-manager/bin:$PATH\"\n"
+const bashEnvSnippet = "\nexport PATH=\"$HOME/.cli-manager/bin:$PATH\"\n"
+const powershellEnvSnippet = "\n$env:PATH = \"$HOME/.cli-manager/bin\" + [IO.Path]::PathSeparator + $env:PATH\n"
+const fishEnvSnippet = "\nset -gx PATH \"$HOME/.cli-manager/bin\" $PATH\n"
+const fishEnvFile = "cli-manager-env.fish"
+
+// posixPathSetup prepends the folder to PATH unless it is already on it
+func posixPathSetup(folder string) string {
+	return fmt.Sprintf("case \":$PATH:\" in *\":%[1]s:\"*) ;; *) export PATH=\"%[1]s:$PATH\" ;; esac", folder)
+}
+
+// fishPathSetup prepends the folder to PATH unless it is already on it
+func fishPathSetup(folder string) string {
+	return fmt.Sprintf("contains -- \"%[1]s\" $PATH; or set -gx PATH \"%[1]s\" $PATH", folder)
+}
+
+// powershellPathSetup prepends the folder to PATH unless it is already on it
+func powershellPathSetup(folder string) string {
+	return fmt.Sprintf("if (-not (($env:PATH -split [IO.Path]::PathSeparator) -contains \"%[1]s\")) "+
+		"{ $env:PATH = \"%[1]s\" + [IO.Path]::PathSeparator + $env:PATH }", folder)
+}
+
+func handlePosixEnv(generate bool, install bool, uninstall bool, rcFile string, snippet string) {
+	switch {
+	case generate:
+		fmt.Println(posixPathSetup(nodeman.GetShimFolder(afero.NewOsFs())))
+	case install, uninstall:
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		scriptPath := filepath.Join(dir, rcFile)
+		if uninstall {
+			uninstallShellSnippets("PATH setup", scriptPath, snippet)
+			return
+		}
+		installEnvSnippet(snippet, scriptPath)
+	default:
+		fmt.Printf("Add the following line to your %s file:\n\n%s", rcFile, snippet)
+	}
+}
+
+func handlePowershellEnv(generate bool, install bool, uninstall bool, core bool) {
+	switch {
+	case generate:
+		fmt.Println(powershellPathSetup(nodeman.GetShimFolder(afero.NewOsFs())))
+	case uninstall:
+		uninstallShellSnippets("PATH setup", getPowershellProfilePath(core), powershellEnvSnippet)
+	case install:
+		installEnvSnippet(powershellEnvSnippet, getPowershellProfilePath(core))
+	default:
+		fmt.Printf("Add the following line to your $PROFILE file:\n\n%s", powershellEnvSnippet)
+	}
+}
+
+func handleFishEnv(generate bool, install bool, uninstall bool) {
+	switch {
+	case generate:
+		fmt.Println(fishPathSetup(nodeman.GetShimFolder(afero.NewOsFs())))
+	case install, uninstall:
+		scriptPath, err := getFishConfigPath(fishEnvFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if uninstall {
+			uninstallShellSnippets("PATH setup", scriptPath, fishEnvSnippet)
+			return
+		}
+		installEnvSnippet(fishEnvSnippet, scriptPath)
+	default:
+		fmt.Printf("Add the following line to your config.fish file:\n\n%s", fishEnvSnippet)
+	}
+}
+
+// installEnvSnippet writes the shims of the installed apps, which installs from earlier versions lack,
+// and puts the shim folder on PATH in the profile
+func installEnvSnippet(snippet string, scriptPath string) {
+	err := nodeman.NewManager(afero.NewOsFs()).WriteShims()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	wrote, err := writeShellSnippet(snippet, scriptPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if wrote {
+		fmt.Printf("Wrote PATH setup to: %s\n", scriptPath)
+	} else {
+		fmt.Printf("PATH setup already installed in: %s\n", scriptPath)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().BoolP("generate", "g", false, "Print the PATH setup for the shell and send to stdout")
+	envCmd.Flags().BoolP("powershell", "p", false, "PATH setup for powershell")
+	envCmd.Flags().BoolP("pwsh", "c", false, "PATH setup for powershell core")
+	envCmd.Flags().BoolP("bash", "b", false, "PATH setup for bash")
+	envCmd.Flags().BoolP("zsh", "z", false, "PATH setup for zsh")
+	envCmd.Flags().BoolP("fish", "f", false, "PATH setup for fish")
+	envCmd.Flags().BoolP("install", "i", false, "Install the PATH setup to the default location.")
+	envCmd.Flags().Bool("uninstall", false, "Remove the PATH setup from the default location.")
+}
@@ -2,65 +2,218 @@ package cmd
 
 import (
 	"fmt"
-	"net/http"
 	"os"
+	"strings"
 
 	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/rdaniels6813/cli-manager/internal/store"
+	"github.com/rdaniels6813/cli-manager/internal/token"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // installCmd represents the install command
 var installCmd = &cobra.Command{
 	Use:   "install",
 	Short: "Install a CLI",
-	Long:  `Install a CLI application for local use.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Install a CLI application for local use, from a registry package spec like typescript@^5 or a git source
+like github:owner/repo#v1.2.0, gitlab:owner/repo#semver:^1, git+https://host/repo.git#main or
+git+ssh://git@host/repo.git#<commit>. Packages in a monorepo folder are installed with ::path:, as in
+github:owner/repo#main::path:packages/cli. Local folders and tarballs like ./my-cli, file:../my-cli or
+./my-cli-1.0.0.tgz are copied into the install, see cli-manager link to use a folder as it is edited.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		nodeVersion, err := nodeman.GetLatestNodeVersion(http.DefaultClient)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+		dist := getDist(cmd)
+		fs := afero.NewOsFs()
+		spec := args[0]
+		if local, ok := nodeman.LocalSpec(spec); ok {
+			spec = local
 		}
-		nodeManager := nodeman.NewManager(afero.NewOsFs())
-		node, err := nodeManager.GetNode(nodeVersion)
+		registryURL, _ := cmd.Flags().GetString("registry")
+		err := setupGitHubPackages(spec, registryURL)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		output, err := node.NpmView(args[0])
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+		options := []func(*nodeman.Manager){nodeman.WithDist(dist), withProgress(cmd), withGenerations(cmd), withRegistry()}
+		if verify, _ := cmd.Flags().GetBool("verify-signature"); verify {
+			options = append(options, nodeman.WithReleaseKeyring(nodeman.GetReleaseKeyringPath(fs)))
 		}
-		engine, err := cmd.Flags().GetString("node-version")
-		if err != nil || engine == "" {
-			engine = output.Engines["node"]
+		nodeManager := nodeman.NewManager(fs, options...)
+		request := &installRequest{spec: spec, flags: getChangedFlags(cmd)}
+		request.nodeVersion, _ = cmd.Flags().GetString("node-version")
+		request.registry = registryURL
+		if app, err := nodeManager.GetCLIApp(spec); err == nil {
+			request.nodeRange = app.NodeRange
+			request.aliases = app.Aliases
+			if request.registry == "" {
+				request.registry = app.Registry
+			}
 		}
-		version, err := nodeman.GetNodeVersionByRangeOrLTS(engine, http.DefaultClient)
+		_, err = installApp(cmd, nodeManager, dist, request)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		installNode, err := nodeManager.GetNode(version)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+	},
+}
+
+// installRequest describes how to install an app
+type installRequest struct {
+	spec string
+	// nodeVersion a node version range used for this install only
+	nodeVersion string
+	// nodeRange the node version range the app is pinned to
+	nodeRange string
+	registry  string
+	aliases   map[string]string
+	flags     []string
+}
+
+// installApp installs the package spec into its own prefix with the node version matching the requested range,
+// the pinned range or the package's engines.node range, in that order
+func installApp(cmd *cobra.Command, nodeManager *nodeman.Manager, dist *nodeman.Dist, request *installRequest) (*nodeman.CLIApp, error) {
+	app := &nodeman.CLIApp{InstallName: request.spec, NodeRange: request.nodeRange, Registry: request.registry,
+		Aliases: request.aliases, Flags: request.flags}
+	output, err := nodeManager.ViewPackage(app, request.spec)
+	if err != nil {
+		return nil, err
+	}
+	engine := request.nodeVersion
+	if engine == "" {
+		engine = request.nodeRange
+	}
+	if engine == "" {
+		engine = output.Engines["node"]
+	}
+	policy := getNodePolicy(cmd, nodeManager, request.spec)
+	version, err := nodeman.ResolveNodeVersion(policy, engine, dist)
+	if err != nil {
+		return nil, err
+	}
+	installNode, err := nodeManager.GetNode(version)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := nodeManager.GetCLIApp(output.Name)
+	if err != nil {
+		previous = nil
+	}
+	prefix, err := nodeManager.InstallApp(app.ConfigureNode(installNode), request.spec, output)
+	if err != nil {
+		return nil, err
+	}
+	app.App = output.Name
+	app.Version = output.Version
+	app.Resolved = output.Dist.Tarball
+	app.Integrity = output.Dist.Integrity
+	app.Commit = output.Commit
+	app.NodeVersion = version
+	app.Path = installNode.BinPath()
+	app.Prefix = prefix
+	app.NodePolicy = string(policy)
+	err = nodeManager.MarkInstalled(app, output.GetBins())
+	if err != nil {
+		if previous == nil || !previous.UsesPrefix(prefix) {
+			nodeManager.RemoveAppPrefix(prefix)
 		}
-		err = installNode.Npm("install", "-g", args[0])
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+		return nil, err
+	}
+	if previous != nil && previous.Prefix == "" {
+		removeAppInstall(nodeManager, previous)
+	}
+	return app, nil
+}
+
+// setupGitHubPackages adds GitHub Packages as the registry of a scoped package published there, with a
+// GitHub token that can read packages, so private CLIs on GitHub install without editing an npmrc
+func setupGitHubPackages(spec string, registryURL string) error {
+	name, _, ok := registry.ParseSpec(spec)
+	if !ok {
+		return nil
+	}
+	cfg, err := registry.LoadConfig()
+	if err != nil {
+		return err
+	}
+	registries, err := getRegistries()
+	if err == nil {
+		err = registries.Apply(cfg)
+	}
+	if err != nil {
+		return err
+	}
+	tokens := token.NewConfigTokenManager(store.GetDefaultStore())
+	client := registry.NewClient(registryHTTPClient, cfg)
+	githubToken, err := client.GitHubPackagesToken(name, registryURL, savedGitHubPackagesToken, func() (string, error) {
+		return tokens.GetNewOrSavedToken([]string{registry.GitHubPackagesScope})
+	})
+	if err != nil || githubToken == "" {
+		return err
+	}
+	// the token stays in the GitHub token store, registries read it from there
+	scope := strings.SplitN(name, "/", 2)[0]
+	_, err = registries.Add(registry.GitHubPackagesRegistry, []string{scope}, "")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Installing %s packages from GitHub Packages\n", scope)
+	return nil
+}
+
+// savedGitHubPackagesToken returns the saved GitHub token that can read packages, without signing in
+func savedGitHubPackagesToken() (string, error) {
+	return token.NewConfigTokenManager(store.GetDefaultStore()).GetSavedToken([]string{registry.GitHubPackagesScope})
+}
+
+// getChangedFlags returns the flags given on the command line, to record how an app was installed
+func getChangedFlags(cmd *cobra.Command) []string {
+	flags := []string{}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		flags = append(flags, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+	})
+	return flags
+}
+
+// recordedOptions returns the manager options for the install flags recorded with the app
+func recordedOptions(app *nodeman.CLIApp) []func(*nodeman.Manager) {
+	options := []func(*nodeman.Manager){}
+	for _, flag := range app.Flags {
+		if flag == "--verify-signature=true" {
+			options = append(options, nodeman.WithReleaseKeyring(nodeman.GetReleaseKeyringPath(afero.NewOsFs())))
 		}
-		err = nodeManager.MarkInstalled(output.Name, output.GetBins(), installNode.BinPath(), args[0])
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+	}
+	return options
+}
+
+// getNodePolicy returns the policy from the flag, the one recorded when the app was last installed,
+// or the configured default, in that order
+func getNodePolicy(cmd *cobra.Command, manager *nodeman.Manager, appName string) nodeman.Policy {
+	value, _ := cmd.Flags().GetString("node-policy")
+	if !cmd.Flags().Changed("node-policy") {
+		if app, err := manager.GetCLIApp(appName); err == nil && app.NodePolicy != "" {
+			value = app.NodePolicy
+		} else {
+			value = getConfig(cmd).NodePolicy
 		}
-	},
+	}
+	policy, err := nodeman.ParsePolicy(value)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return policy
 }
 
 func init() {
-	installCmd.Flags().StringP("node-version", "n", "", "Specify a node version to use for install: --node-version 12.x")
+	installCmd.Flags().StringP("node-version", "n", "", "Specify an npm style node version range to use for install: --node-version ^18.12")
+	installCmd.Flags().String("node-policy", "",
+		"Policy for choosing the node version: lts, active-lts, maintenance, current, exact or lts/<codename>")
+	installCmd.Flags().String("registry", "", "The npm registry to install the CLI from")
+	installCmd.Flags().Bool("verify-signature", false,
+		"Verify the signature of the node release checksums using the node release keys built into cli-manager, "+
+			"or the keyring at ~/.cli-manager/node-release-keys.asc when it exists")
 	rootCmd.AddCommand(installCmd)
 }
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// linkCmd represents the link command
+var linkCmd = &cobra.Command{
+	Use:   "link [dir]",
+	Short: "Install a CLI from a local folder, running it from the folder as it is edited",
+	Long: `Install the CLI in a local folder, the current folder by default, by linking to it instead of copying it,
+so local edits take effect without reinstalling. The CLI runs with the dependencies installed in the folder,
+so run npm install there first.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		spec, ok := nodeman.LocalSpec("link:" + dir)
+		if info, err := os.Stat(strings.TrimPrefix(spec, "link:")); !ok || err != nil || !info.IsDir() {
+			fmt.Printf("%s is not a local folder\n", dir)
+			os.Exit(1)
+		}
+		dist := getDist(cmd)
+		manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(dist), withProgress(cmd), withGenerations(cmd), withRegistry())
+		request := &installRequest{spec: spec, flags: getChangedFlags(cmd)}
+		request.nodeVersion, _ = cmd.Flags().GetString("node-version")
+		app, err := installApp(cmd, manager, dist, request)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Linked %s to %s\n", app.App, strings.TrimPrefix(spec, "link:"))
+	},
+}
+
+func init() {
+	linkCmd.Flags().StringP("node-version", "n", "", "Specify an npm style node version range to use: --node-version ^18.12")
+	linkCmd.Flags().String("node-policy", "",
+		"Policy for choosing the node version: lts, active-lts, maintenance, current, exact or lts/<codename>")
+	rootCmd.AddCommand(linkCmd)
+}
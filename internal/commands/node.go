@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/rdaniels6813/cli-manager/internal/npmrange"
+	"github.com/rdaniels6813/cli-manager/internal/progress"
+	"github.com/rdaniels6813/cli-manager/internal/promptui"
+	"github.com/spf13/afero"
+
+	"github.com/spf13/cobra"
+)
+
+// nodeCmd represents the node command
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Manage the node versions used by installed CLIs",
+	Long:  ``,
+}
+
+// nodeListCmd represents the node list command
+var nodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the downloaded node versions",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := nodeman.NewManager(afero.NewOsFs())
+		runtimes, err := manager.GetNodeRuntimes()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tSIZE\tAPPS")
+		for _, runtime := range runtimes {
+			fmt.Fprintf(w, "v%s\t%s\t%s\n", runtime.Version, progress.FormatBytes(runtime.Size), strings.Join(runtime.Apps, ", "))
+		}
+		w.Flush()
+	},
+}
+
+// nodeInstallCmd represents the node install command
+var nodeInstallCmd = &cobra.Command{
+	Use:   "install [range]",
+	Short: "Download the latest node version matching the range",
+	Long:  `Download the latest node version allowed by the node policy that matches the range.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dist := getDist(cmd)
+		engine := ""
+		if len(args) > 0 {
+			engine = args[0]
+		}
+		value, _ := cmd.Flags().GetString("node-policy")
+		if value == "" {
+			value = getConfig(cmd).NodePolicy
+		}
+		policy, err := nodeman.ParsePolicy(value)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		version, err := nodeman.ResolveNodeVersion(policy, engine, dist)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(dist), withProgress(cmd))
+		node, err := manager.GetNode(version)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Installed node v%s to: %s\n", version, node.BinPath())
+	},
+}
+
+// nodeRemoveCmd represents the node remove command
+var nodeRemoveCmd = &cobra.Command{
+	Use:   "remove [version]",
+	Short: "Remove a downloaded node version",
+	Long:  `Remove a downloaded node version, as long as no installed CLI uses it.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := nodeman.NewManager(afero.NewOsFs())
+		version, err := manager.RemoveNode(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed node v%s\n", version)
+	},
+}
+
+// nodePruneCmd represents the node prune command
+var nodePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the downloaded node versions no installed CLI uses",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := nodeman.NewManager(afero.NewOsFs())
+		removed, err := manager.PruneNodes()
+		for _, version := range removed {
+			fmt.Printf("Removed node v%s\n", version)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(removed) == 0 {
+			fmt.Println("No unused node versions to remove")
+		}
+	},
+}
+
+// nodeAuditCmd represents the node audit command
+var nodeAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check the node versions used by installed CLIs for security releases and end-of-life",
+	Long: `Report, for each node version installed CLIs use, whether a newer release of the same major contains
+security fixes and whether the major is past its end-of-life date, then offer to move the affected CLIs
+to the latest release of their major. CLIs pinned to a node range excluding that release are left alone.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(getDist(cmd)), withProgress(cmd), withGenerations(cmd), withRegistry())
+		audits, err := manager.AuditNodes()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(audits) == 0 {
+			fmt.Println("No installed CLIs use a node version managed by cli-manager")
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tAPPS\tSTATUS")
+		for _, audit := range audits {
+			fmt.Fprintf(w, "v%s\t%s\t%s\n", audit.Version, strings.Join(audit.Apps, ", "), auditStatus(audit))
+		}
+		w.Flush()
+		yes, _ := cmd.Flags().GetBool("yes")
+		prompter := &promptui.CLIPrompter{}
+		for _, audit := range audits {
+			if !audit.Vulnerable() {
+				continue
+			}
+			apps := movableApps(manager, audit)
+			if len(apps) == 0 {
+				continue
+			}
+			names := make([]string, 0, len(apps))
+			for _, app := range apps {
+				names = append(names, app.App)
+			}
+			if !yes {
+				if !progress.IsTerminal(os.Stdin) {
+					fmt.Printf("Run with --yes to move %s to node v%s\n", strings.Join(names, ", "), audit.Latest)
+					continue
+				}
+				move, err := prompter.PromptConfirm(fmt.Sprintf("Move %s to node v%s", strings.Join(names, ", "), audit.Latest))
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				if !move {
+					continue
+				}
+			}
+			for _, app := range apps {
+				err = reinstallApp(manager, app, audit.Latest, false)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				fmt.Printf("Moved %s to node v%s\n", app.App, audit.Latest)
+			}
+		}
+	},
+}
+
+// movableApps returns the apps using the audited node version that can move to its latest release,
+// reporting the ones pinned to a node range that excludes it
+func movableApps(manager *nodeman.Manager, audit *nodeman.NodeAudit) []*nodeman.CLIApp {
+	apps := []*nodeman.CLIApp{}
+	for _, name := range audit.Apps {
+		app, err := manager.GetCLIApp(name)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if app.NodeRange != "" {
+			nodeRange, err := npmrange.Parse(app.NodeRange)
+			if err != nil || !nodeRange.ContainsString(audit.Latest) {
+				fmt.Printf("Skipped %s, it is pinned to node %s which excludes v%s, pin it to a wider range to move it\n",
+					app.App, app.NodeRange, audit.Latest)
+				continue
+			}
+		}
+		apps = append(apps, app)
+	}
+	return apps
+}
+
+// auditStatus describes the problems found with a node version
+func auditStatus(audit *nodeman.NodeAudit) string {
+	problems := []string{}
+	if audit.Vulnerable() {
+		problems = append(problems, fmt.Sprintf("security fixes in v%s", audit.SecurityRelease))
+	}
+	if audit.EndOfLife() {
+		problems = append(problems, fmt.Sprintf("end-of-life since %s", audit.End))
+	}
+	if len(problems) == 0 {
+		return "ok"
+	}
+	return strings.Join(problems, "; ")
+}
+
+func init() {
+	rootCmd.AddCommand(nodeCmd)
+	nodeCmd.AddCommand(nodeListCmd)
+	nodeCmd.AddCommand(nodeInstallCmd)
+	nodeInstallCmd.Flags().String("node-policy", "",
+		"Policy for choosing the node version: lts, active-lts, maintenance, current, exact or lts/<codename>")
+	nodeCmd.AddCommand(nodeRemoveCmd)
+	nodeCmd.AddCommand(nodePruneCmd)
+	nodeCmd.AddCommand(nodeAuditCmd)
+	nodeAuditCmd.Flags().BoolP("yes", "y", false, "Move CLIs affected by security releases without asking")
+}
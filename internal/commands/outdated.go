@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+
+	"github.com/spf13/cobra"
+)
+
+// outdatedCmd represents the outdated command
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated [appName...]",
+	Short: "List installed CLIs with newer releases",
+	Long: `List the installed version of each CLI, the newest version matching the version range or dist-tag
+it was installed with and the latest dist-tag. Exits with status 1 when any CLI is outdated, or 2 when
+the releases of any CLI could not be checked.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := nodeman.NewManager(afero.NewOsFs(), withRegistry())
+		apps, err := selectApps(manager, args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		statuses := make([]*nodeman.AppStatus, 0, len(apps))
+		outdated := false
+		failed := false
+		for _, app := range apps {
+			status := manager.GetAppStatus(app)
+			outdated = outdated || status.Outdated
+			failed = failed || status.Error != ""
+			statuses = append(statuses, status)
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(statuses)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		} else {
+			printAppStatuses(statuses)
+		}
+		if failed {
+			os.Exit(2)
+		}
+		if outdated {
+			os.Exit(1)
+		}
+	},
+}
+
+// selectApps returns the named apps, or every installed app when none are named
+func selectApps(manager *nodeman.Manager, names []string) ([]*nodeman.CLIApp, error) {
+	if len(names) == 0 {
+		return manager.GetCLIApps(), nil
+	}
+	apps := make([]*nodeman.CLIApp, 0, len(names))
+	for _, name := range names {
+		app, err := manager.GetCLIApp(name)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+func printAppStatuses(statuses []*nodeman.AppStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APP\tINSTALLED\tWANTED\tLATEST\tNOTES")
+	for _, status := range statuses {
+		notes := status.Error
+		if status.Deprecated != "" {
+			notes = fmt.Sprintf("deprecated: %s", status.Deprecated)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", status.App, orDash(status.Installed), orDash(status.Wanted),
+			orDash(status.Latest), notes)
+	}
+	w.Flush()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().Bool("json", false, "Print the result as JSON")
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/rdaniels6813/cli-manager/internal/npmrange"
+	"github.com/spf13/afero"
+
+	"github.com/spf13/cobra"
+)
+
+// pinCmd represents the pin command
+var pinCmd = &cobra.Command{
+	Use:   "pin [appName] [nodeRange]",
+	Short: "Pin a CLI to an npm style node version range",
+	Long: `Pin a CLI to an npm style node version range, used instead of the package's engines.node range
+whenever the CLI is installed or rebased. The CLI is rebased right away when its current node version
+is outside the range.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		nodeRange, err := npmrange.Parse(args[1])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		dist := getDist(cmd)
+		manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(dist), withProgress(cmd), withGenerations(cmd), withRegistry())
+		app, err := manager.GetCLIApp(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		// the range is only recorded once the app is installed under it, a refused or failed rebase exits first
+		if current, err := manager.GetCLIAppNodeVersion(app); err != nil || !nodeRange.ContainsString(current) {
+			app.NodeRange = args[1]
+			force, _ := cmd.Flags().GetBool("force")
+			rebaseApp(cmd, manager, dist, app, args[1], force)
+		}
+		err = manager.PinNodeRange(app.App, args[1])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Pinned %s to node %s\n", app.App, args[1])
+	},
+}
+
+// rebaseCmd represents the rebase command
+var rebaseCmd = &cobra.Command{
+	Use:   "rebase [appName]",
+	Short: "Reinstall a CLI with a newly resolved node version",
+	Long: `Reinstall the installed version of a CLI with the latest node version matching --node, the range
+the CLI is pinned to or the package's engines.node range, in that order, and remove the install from the
+previous node version.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dist := getDist(cmd)
+		manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(dist), withProgress(cmd), withGenerations(cmd), withRegistry())
+		app, err := manager.GetCLIApp(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		engine, _ := cmd.Flags().GetString("node")
+		if engine == "" {
+			engine = app.NodeRange
+		}
+		// the installed version is reinstalled, so its own engines.node range applies
+		if engine == "" {
+			output, err := manager.ViewPackage(app, installedSpec(app))
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			engine = output.Engines["node"]
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		rebaseApp(cmd, manager, dist, app, engine, force)
+	},
+}
+
+// rebaseApp moves the app to the latest node version matching the range under the app's node policy
+func rebaseApp(cmd *cobra.Command, manager *nodeman.Manager, dist *nodeman.Dist, app *nodeman.CLIApp, engine string, force bool) {
+	policy := getNodePolicy(cmd, manager, app.App)
+	version, err := nodeman.ResolveNodeVersion(policy, engine, dist)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	err = reinstallApp(manager, app, version, force)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Rebased %s on node v%s\n", app.App, version)
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(rebaseCmd)
+	pinCmd.Flags().Bool("force", false, "Rebase even if the node version is outside the package's engines.node range")
+	rebaseCmd.Flags().String("node", "", "Specify an npm style node version range to rebase on: --node ^18.12")
+	rebaseCmd.Flags().Bool("force", false, "Rebase even if the node version is outside the package's engines.node range")
+	rebaseCmd.Flags().String("node-policy", "",
+		"Policy for choosing the node version: lts, active-lts, maintenance, current, exact or lts/<codename>")
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rdaniels6813/cli-manager/internal/progress"
+	"github.com/rdaniels6813/cli-manager/internal/promptui"
+
+	"github.com/spf13/cobra"
+)
+
+// registryCmd represents the registry command
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Manage the npm registries CLIs are installed from",
+	Long: `Manage private npm registries and the scopes installed from them. Their tokens are kept in
+the cli-manager auth store and only handed to npm through a temporary npmrc while it runs, so they never
+end up in your npm config.`,
+}
+
+// registryAddCmd represents the registry add command
+var registryAddCmd = &cobra.Command{
+	Use:   "add [url]",
+	Short: "Add a registry, or change the scopes & token of one",
+	Long: `Add a registry, installing the packages of the scopes given with --scope from it.
+The token is read from --token, or asked for when running in a terminal.`,
+	Example: "  cli-manager registry add https://npm.corp.example.com/ --scope @corp",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scopes, _ := cmd.Flags().GetStringSlice("scope")
+		token, _ := cmd.Flags().GetString("token")
+		if !cmd.Flags().Changed("token") && progress.IsTerminal(os.Stdin) {
+			prompter := &promptui.CLIPrompter{}
+			var err error
+			token, err = prompter.PromptPassword(fmt.Sprintf("Token for %s (empty for none)", args[0]))
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+		registries, err := getRegistries()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		entry, err := registries.Add(args[0], scopes, strings.TrimSpace(token))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Added registry %s\n", entry.URL)
+	},
+}
+
+// registryListCmd represents the registry list command
+var registryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the registries added to cli-manager",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		registries, err := getRegistries()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "REGISTRY\tSCOPES\tTOKEN")
+		for _, entry := range registries.Entries {
+			token := "no"
+			if registries.HasToken(entry) {
+				token = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", entry.URL, orDash(strings.Join(entry.Scopes, ", ")), token)
+		}
+		w.Flush()
+	},
+}
+
+// registryRemoveCmd represents the registry remove command
+var registryRemoveCmd = &cobra.Command{
+	Use:   "remove [url]",
+	Short: "Remove a registry along with its token",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		registries, err := getRegistries()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		err = registries.Remove(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed registry %s\n", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+	registryCmd.AddCommand(registryAddCmd)
+	registryAddCmd.Flags().StringSlice("scope", []string{}, "Scope to install from the registry, like @corp, can be repeated")
+	registryAddCmd.Flags().String("token", "", "Auth token for the registry")
+	registryCmd.AddCommand(registryListCmd)
+	registryCmd.AddCommand(registryRemoveCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rdaniels6813/cli-manager/internal/gitsource"
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+)
+
+// reinstallApp installs the app's package with the given node version, records the new install
+// and removes the previous one. The installed version is kept, git sources stay on the installed commit.
+// Node versions outside the package's engines.node range are refused unless forced.
+func reinstallApp(manager *nodeman.Manager, app *nodeman.CLIApp, version string, force bool) error {
+	node, err := manager.GetNode(version)
+	if err != nil {
+		return err
+	}
+	if node.BinPath() == app.Path {
+		return nil
+	}
+	spec := installedSpec(app)
+	output, err := manager.ViewPackage(app, spec)
+	if err != nil {
+		return err
+	}
+	return replaceApp(manager, app, node, spec, output, version, force)
+}
+
+// installedSpec returns the package spec of the installed version of the app
+func installedSpec(app *nodeman.CLIApp) string {
+	if source, ok := gitsource.Parse(app.InstallName); ok && app.Commit != "" {
+		return source.Pinned(app.Commit)
+	}
+	if name, _, ok := registry.ParseSpec(app.InstallName); ok && app.Version != "" {
+		return fmt.Sprintf("%s@%s", name, app.Version)
+	}
+	return app.InstallName
+}
+
+// replaceApp installs the package spec described by output in place of the app, keeping the options
+// the app was installed with. The previous version is kept for rollback, unless it was installed
+// into the shared global prefix of its node version.
+func replaceApp(manager *nodeman.Manager, app *nodeman.CLIApp, node nodeman.Node, spec string,
+	output *nodeman.NpmViewResponse, version string, force bool) error {
+	supported, err := output.SupportsNode(version)
+	if err != nil {
+		return err
+	}
+	if !supported && !force {
+		return fmt.Errorf("%s requires node %s, use --force to install it with node v%s anyway",
+			app.App, output.Engines["node"], version)
+	}
+	previous := *app
+	prefix, err := manager.InstallApp(app.ConfigureNode(node), spec, output)
+	if err != nil {
+		return err
+	}
+	installed := *app
+	installed.App = output.Name
+	installed.Version = output.Version
+	installed.Resolved = output.Dist.Tarball
+	installed.Integrity = output.Dist.Integrity
+	installed.Commit = output.Commit
+	installed.NodeVersion = version
+	installed.Path = node.BinPath()
+	installed.Prefix = prefix
+	err = manager.MarkInstalled(&installed, output.GetBins())
+	if err != nil {
+		if !previous.UsesPrefix(prefix) {
+			manager.RemoveAppPrefix(prefix)
+		}
+		return err
+	}
+	if previous.Prefix == "" {
+		removeAppInstall(manager, &previous)
+	}
+	return nil
+}
+
+// removeAppInstall removes an install from the shared global prefix of its node version,
+// reporting failures since the record is already gone
+func removeAppInstall(manager *nodeman.Manager, app *nodeman.CLIApp) {
+	err := manager.GetNodeByPath(app.Path).Npm("remove", "-g", app.App)
+	if err != nil {
+		fmt.Printf("Failed to remove the previous install of %s: %s\n", app.App, err)
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+
+	"github.com/spf13/cobra"
+)
+
+// rollbackCmd represents the rollback command
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback [appName]",
+	Short: "Switch a CLI back to a previous version kept on disk",
+	Long: `Switch a CLI back to the previous version kept on disk, or the version given with --to,
+without any network access. See cli-manager history for the versions available.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := nodeman.NewManager(afero.NewOsFs())
+		to, _ := cmd.Flags().GetString("to")
+		app, err := manager.Rollback(args[0], to)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Rolled %s back to %s\n", app.App, orDash(app.Version))
+	},
+}
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history [appName]",
+	Short: "List the versions of a CLI kept on disk",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := nodeman.NewManager(afero.NewOsFs())
+		history, err := manager.GetAppHistory(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNODE\tINSTALLED\tNOTES")
+		for i, generation := range history {
+			installed := "-"
+			if !generation.UpdatedAt.IsZero() {
+				installed = generation.UpdatedAt.Local().Format("2006-01-02 15:04")
+			}
+			current := ""
+			if i == 0 {
+				current = "current"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", orDash(generation.Version), orDash(generation.NodeVersion), installed, current)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(historyCmd)
+	rollbackCmd.Flags().String("to", "", "The version to roll back to, the previous one by default")
+}
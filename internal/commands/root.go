@@ -2,9 +2,20 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/rdaniels6813/cli-manager/internal/config"
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/rdaniels6813/cli-manager/internal/progress"
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/rdaniels6813/cli-manager/internal/store"
+	"github.com/rdaniels6813/cli-manager/internal/util"
 	"github.com/rdaniels6813/cli-manager/internal/version"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
@@ -25,5 +36,143 @@ func Execute() {
 	}
 }
 
+// getConfig loads the user config, applying any overrides from global flags
+func getConfig(cmd *cobra.Command) *config.Config {
+	cfg, err := config.Load(afero.NewOsFs())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if mirror, _ := cmd.Flags().GetString("node-mirror"); mirror != "" {
+		cfg.NodeMirror = mirror
+	}
+	return cfg
+}
+
+// getDist returns the node.js distribution configured for the user
+func getDist(cmd *cobra.Command) *nodeman.Dist {
+	cfg := getConfig(cmd)
+	fs := afero.NewOsFs()
+	ttl := nodeman.DefaultIndexTTL
+	if cfg.NodeIndexTTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(cfg.NodeIndexTTL)
+		if err != nil {
+			fmt.Printf("Invalid node index TTL %q: %s\n", cfg.NodeIndexTTL, err)
+			os.Exit(1)
+		}
+	}
+	offline, _ := cmd.Flags().GetBool("offline")
+	var localVersions []string
+	if offline {
+		runtimes, err := nodeman.NewManager(fs).GetNodeRuntimes()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, runtime := range runtimes {
+			localVersions = append(localVersions, runtime.Version)
+		}
+	}
+	return nodeman.NewDist(http.DefaultClient, func(d *nodeman.Dist) {
+		d.BaseURL = cfg.NodeMirror
+		d.UnofficialBaseURL = cfg.NodeUnofficialMirror
+		d.Username = cfg.NodeMirrorUsername
+		d.Password = cfg.NodeMirrorPassword
+		d.Token = cfg.NodeMirrorToken
+		d.ScheduleURL = cfg.NodeScheduleURL
+		d.CacheDir = filepath.Join(util.GetCliManagerFolder(fs), "cache")
+		d.CacheTTL = ttl
+		d.Offline = offline
+		d.LocalVersions = localVersions
+	})
+}
+
+// withProgress reports node downloads on stderr unless the quiet flag is set
+func withProgress(cmd *cobra.Command) func(*nodeman.Manager) {
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		return nodeman.WithProgress(nil, false)
+	}
+	return nodeman.WithProgress(os.Stderr, progress.IsTerminal(os.Stderr))
+}
+
+// registryHTTPClient requests package metadata, giving up on registries that stop responding
+var registryHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// withRegistry reads package metadata from the registries in the user's npm config and the ones
+// added with cli-manager registry add, whose scopes & tokens npm reads from an isolated npmrc
+func withRegistry() func(*nodeman.Manager) {
+	cfg, err := registry.LoadConfig()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	registries, err := getRegistries()
+	if err == nil {
+		err = registries.Apply(cfg)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	userconfig, err := registry.UserConfigPath()
+	if err == nil {
+		err = removeLegacyNpmrc()
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	client := registry.NewClient(registryHTTPClient, cfg)
+	return func(m *nodeman.Manager) {
+		nodeman.WithRegistry(client)(m)
+		if len(registries.Entries) > 0 {
+			nodeman.WithNpmrc(func(path string) error {
+				return registries.WriteNpmrc(path, userconfig)
+			})(m)
+		}
+	}
+}
+
+// removeLegacyNpmrc deletes the npmrc earlier versions kept in the cli-manager folder,
+// so no copies of registry tokens are left behind
+func removeLegacyNpmrc() error {
+	err := os.Remove(nodeman.GetNpmrcPath(afero.NewOsFs()))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// getRegistries reads the registries added with cli-manager registry add
+func getRegistries() (*registry.Registries, error) {
+	path := filepath.Join(util.GetCliManagerFolder(afero.NewOsFs()), "registries.json")
+	registries, err := registry.LoadRegistries(path, store.GetDefaultStore())
+	if err != nil {
+		return nil, err
+	}
+	registries.GitHubToken = savedGitHubPackagesToken
+	return registries, nil
+}
+
+// withGenerations keeps the configured number of previous app versions for rollback
+func withGenerations(cmd *cobra.Command) func(*nodeman.Manager) {
+	cfg := getConfig(cmd)
+	if cfg.Generations == "" {
+		return nodeman.WithGenerations(nodeman.DefaultGenerations)
+	}
+	generations, err := strconv.Atoi(cfg.Generations)
+	if err != nil || generations < 0 {
+		fmt.Printf("Invalid number of generations %q, expected a number of versions to keep\n", cfg.Generations)
+		os.Exit(1)
+	}
+	return nodeman.WithGenerations(generations)
+}
+
 func init() {
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Do not report download progress")
+	rootCmd.PersistentFlags().Bool("offline", false,
+		"Resolve node versions only from the cached release index and already downloaded versions")
+	rootCmd.PersistentFlags().String("node-mirror", "",
+		fmt.Sprintf("Base URL of the node.js distribution mirror (default %s)", nodeman.DefaultDistURL))
 }
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rdaniels6813/cli-manager/internal/progress"
+	"github.com/rdaniels6813/cli-manager/internal/promptui"
+	"github.com/rdaniels6813/cli-manager/internal/util"
+	"github.com/spf13/afero"
+
+	"github.com/spf13/cobra"
+)
+
+// selfCmd represents the self command
+var selfCmd = &cobra.Command{
+	Use:   "self",
+	Short: "Manage cli-manager itself",
+	Long:  ``,
+}
+
+// selfUninstallCmd represents the self uninstall command
+var selfUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove cli-manager's shell setup along with every installed app & node version",
+	Long: `Removes the completion, aliases and PATH setup cli-manager added to shell profiles, then deletes the
+cli-manager folder holding the installed apps, node versions and stored tokens. The cli-manager binary
+itself is left for you to delete.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		folder := util.GetCliManagerFolder(afero.NewOsFs())
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes {
+			if !progress.IsTerminal(os.Stdin) {
+				fmt.Printf("Run with --yes to delete %s\n", folder)
+				os.Exit(1)
+			}
+			prompter := &promptui.CLIPrompter{}
+			remove, err := prompter.PromptConfirm(fmt.Sprintf("Delete %s and every app installed with cli-manager", folder))
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if !remove {
+				return
+			}
+		}
+		profiles, err := getShellProfileSnippets()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for path, snippets := range profiles {
+			removed, err := removeShellSnippets(path, snippets...)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if removed {
+				fmt.Printf("Removed cli-manager from: %s\n", path)
+			}
+		}
+		err = os.RemoveAll(folder)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Deleted %s\n", folder)
+	},
+}
+
+// getShellProfileSnippets returns every snippet cli-manager can add to shell profiles, keyed by the file it goes in
+func getShellProfileSnippets() (map[string][]string, error) {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	profiles := map[string][]string{
+		filepath.Join(dir, ".zshrc"): {
+			zshCompletionSnippet, zshAliasesSnippet, legacyZshAliasesSnippet, zshEnvSnippet,
+		},
+		filepath.Join(dir, ".bashrc"): {
+			bashCompletionSnippet, bashAliasesSnippet, legacyBashAliasesSnippet, bashEnvSnippet,
+		},
+	}
+	for _, core := range []bool{false, true} {
+		profiles[getPowershellProfilePath(core)] = []string{
+			powershellCompletionSnippet, powershellAliasesSnippet, legacyPowershellAliasesSnippet, powershellEnvSnippet,
+		}
+	}
+	for file, snippet := range map[string]string{
+		fishCompletionFile: fishCompletionSnippet,
+		fishAliasesFile:    fishAliasesSnippet,
+		fishEnvFile:        fishEnvSnippet,
+	} {
+		path, err := getFishConfigPath(file)
+		if err != nil {
+			return nil, err
+		}
+		profiles[path] = []string{snippet}
+	}
+	return profiles, nil
+}
+
+func init() {
+	rootCmd.AddCommand(selfCmd)
+	selfCmd.AddCommand(selfUninstallCmd)
+	selfUninstallCmd.Flags().BoolP("yes", "y", false, "Uninstall without asking")
+}
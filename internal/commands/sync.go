@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/rdaniels6813/cli-manager/internal/manifest"
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/spf13/afero"
+
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Install & update CLIs until they match a cli-manager.yaml manifest",
+	Long: `Install the CLIs listed in the manifest that are missing, reinstall those whose version range, node range
+or registry changed and update the rest. With --prune, CLIs that are not in the manifest are uninstalled.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		prune, _ := cmd.Flags().GetBool("prune")
+		applyManifest(cmd, file, true, prune)
+	},
+}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [manifest]",
+	Short: "Install the CLIs from a cli-manager.yaml manifest that are not installed yet",
+	Long:  `Install the CLIs listed in the manifest that are missing, leaving the installed CLIs as they are.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := manifest.DefaultFile
+		if len(args) > 0 {
+			file = args[0]
+		}
+		applyManifest(cmd, file, false, false)
+	},
+}
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a cli-manager.yaml manifest of the installed CLIs",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := nodeman.NewManager(afero.NewOsFs())
+		result := &manifest.Manifest{Apps: []manifest.App{}}
+		for _, app := range manager.GetCLIApps() {
+			name, version, ok := registry.ParseSpec(app.InstallName)
+			if !ok {
+				name, version = app.InstallName, ""
+			}
+			result.Apps = append(result.Apps, manifest.App{
+				Package:  name,
+				Version:  version,
+				Node:     app.NodeRange,
+				Registry: app.Registry,
+				Aliases:  app.Aliases,
+			})
+		}
+		output, _ := cmd.Flags().GetString("output")
+		out := os.Stdout
+		if output != "" {
+			var err error
+			out, err = os.Create(output)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer out.Close()
+		}
+		err := result.Write(out)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+// applyManifest installs the CLIs in the manifest that are missing, bringing installed ones in line with it
+// when update is set and uninstalling those not in the manifest when prune is set
+func applyManifest(cmd *cobra.Command, file string, update bool, prune bool) {
+	m, err := manifest.Load(file)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	for _, app := range m.Apps {
+		err = setupGitHubPackages(app.Spec(), app.Registry)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	dist := getDist(cmd)
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(dist), withProgress(cmd), withGenerations(cmd), withRegistry())
+	results := []*updateResult{}
+	listed := map[string]bool{}
+	for i := range m.Apps {
+		wanted := &m.Apps[i]
+		existing := findManifestApp(manager, wanted.Package)
+		var result *updateResult
+		switch {
+		case existing == nil:
+			result = installManifestApp(cmd, manager, dist, wanted, "installed")
+		case !update:
+			result = &updateResult{app: existing.App, from: existing.Version, to: existing.Version, node: existing.NodeVersion,
+				action: "already installed"}
+		case existing.InstallName != wanted.Spec() || existing.NodeRange != wanted.Node || existing.Registry != wanted.Registry:
+			result = installManifestApp(cmd, manager, dist, wanted, "reinstalled")
+			result.from = existing.Version
+		default:
+			result = updateApp(cmd, manager, dist, existing)
+			if result.err == nil && !reflect.DeepEqual(existing.Aliases, wanted.Aliases) {
+				result.err = manager.SetAliases(existing.App, wanted.Aliases)
+				if result.action == "" {
+					result.action = "aliases updated"
+				}
+			}
+		}
+		listed[result.app] = true
+		if existing != nil {
+			// a failed reinstall reports the manifest's package, which may not be the name the app is installed as
+			listed[existing.App] = true
+		}
+		results = append(results, result)
+	}
+	if prune {
+		for _, app := range manager.GetCLIApps() {
+			if listed[app.App] {
+				continue
+			}
+			result := &updateResult{app: app.App, from: app.Version, node: app.NodeVersion, action: "removed"}
+			result.err = uninstallApp(manager, app)
+			results = append(results, result)
+		}
+	}
+	printUpdateResults(results)
+	for _, result := range results {
+		if result.err != nil {
+			os.Exit(1)
+		}
+	}
+}
+
+// findManifestApp returns the installed app for the package of a manifest entry
+func findManifestApp(manager *nodeman.Manager, pkg string) *nodeman.CLIApp {
+	for _, app := range manager.GetCLIApps() {
+		name, _, ok := registry.ParseSpec(app.InstallName)
+		if app.App == pkg || app.InstallName == pkg || (ok && name == pkg) {
+			return app
+		}
+	}
+	return nil
+}
+
+func installManifestApp(cmd *cobra.Command, manager *nodeman.Manager, dist *nodeman.Dist, wanted *manifest.App,
+	action string) *updateResult {
+	result := &updateResult{app: wanted.Package}
+	app, err := installApp(cmd, manager, dist, &installRequest{
+		spec:      wanted.Spec(),
+		nodeRange: wanted.Node,
+		registry:  wanted.Registry,
+		aliases:   wanted.Aliases,
+	})
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.app = app.App
+	result.to = app.Version
+	result.node = app.NodeVersion
+	result.action = action
+	return result
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(exportCmd)
+	syncCmd.Flags().StringP("file", "f", manifest.DefaultFile, "The manifest to sync with")
+	syncCmd.Flags().Bool("prune", false, "Uninstall the CLIs that are not in the manifest")
+	exportCmd.Flags().StringP("output", "o", "", "Write the manifest to a file instead of stdout")
+}
@@ -24,17 +24,32 @@ var uninstallCmd = &cobra.Command{
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		node := manager.GetNodeByPath(app.Path)
-		err = node.Npm("remove", "-g", app.App)
+		err = uninstallApp(manager, app)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		err = manager.MarkUninstalled(app.App)
+	},
+}
+
+// uninstallApp removes the app along with the previous versions kept for rollback
+func uninstallApp(manager *nodeman.Manager, app *nodeman.CLIApp) error {
+	var err error
+	if app.Prefix != "" {
+		err = manager.RemoveAppPrefix(app.Prefix)
+	} else {
+		err = manager.GetNodeByPath(app.Path).Npm("remove", "-g", app.App)
+	}
+	if err != nil {
+		return err
+	}
+	for _, generation := range app.Previous {
+		err = manager.RemoveAppPrefix(generation.Prefix)
 		if err != nil {
-			fmt.Println(err)
+			return err
 		}
-	},
+	}
+	return manager.MarkUninstalled(app.App)
 }
 
 func init() {
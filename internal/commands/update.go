@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/spf13/afero"
+
+	"github.com/spf13/cobra"
+)
+
+// updateResult the outcome of updating one app
+type updateResult struct {
+	app  string
+	from string
+	to   string
+	node string
+	err  error
+	// action what was done, empty when the app was already up to date
+	action string
+}
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update [appName...]",
+	Short: "Update installed CLIs within the version range or dist-tag they were installed with",
+	Long: `Update installed CLIs to the newest version matching the version range or dist-tag they were installed with,
+or for git sources to the commit their branch or semver tag range points at now, keeping their install options. The node version is only re-resolved when the new version's engines.node
+range excludes the current one.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		if len(args) == 0 && !all {
+			fmt.Println("Specify the CLIs to update, or --all to update every installed CLI")
+			os.Exit(1)
+		}
+		dist := getDist(cmd)
+		options := []func(*nodeman.Manager){nodeman.WithDist(dist), withProgress(cmd), withGenerations(cmd), withRegistry()}
+		manager := nodeman.NewManager(afero.NewOsFs(), options...)
+		apps, err := selectApps(manager, args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		results := make([]*updateResult, 0, len(apps))
+		failed := false
+		for _, app := range apps {
+			appManager := nodeman.NewManager(afero.NewOsFs(), append(options, recordedOptions(app)...)...)
+			result := updateApp(cmd, appManager, dist, app)
+			failed = failed || result.err != nil
+			results = append(results, result)
+		}
+		printUpdateResults(results)
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// updateApp installs the wanted version of the app, moving it to another node version only when required
+func updateApp(cmd *cobra.Command, manager *nodeman.Manager, dist *nodeman.Dist, app *nodeman.CLIApp) *updateResult {
+	status := manager.GetAppStatus(app)
+	result := &updateResult{app: app.App, from: status.Installed, to: status.Wanted, node: app.NodeVersion}
+	if status.Error != "" {
+		result.err = fmt.Errorf("%s", status.Error)
+		return result
+	}
+	if status.Wanted == status.Installed {
+		return result
+	}
+	spec := app.InstallName
+	if name, _, ok := registry.ParseSpec(app.InstallName); ok {
+		spec = fmt.Sprintf("%s@%s", name, status.Wanted)
+	}
+	output, err := manager.ViewPackage(app, spec)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	version, err := manager.GetCLIAppNodeVersion(app)
+	if supported, _ := output.SupportsNode(version); err != nil || !supported {
+		engine := app.NodeRange
+		if engine == "" {
+			engine = output.Engines["node"]
+		}
+		version, err = nodeman.ResolveNodeVersion(getNodePolicy(cmd, manager, app.App), engine, dist)
+		if err != nil {
+			result.err = err
+			return result
+		}
+	}
+	node, err := manager.GetNode(version)
+	if err == nil {
+		err = replaceApp(manager, app, node, spec, output, version, false)
+	}
+	result.node = version
+	result.err = err
+	if err == nil {
+		result.action = "updated"
+	}
+	return result
+}
+
+func printUpdateResults(results []*updateResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APP\tFROM\tTO\tNODE\tRESULT")
+	for _, result := range results {
+		outcome := "up to date"
+		switch {
+		case result.err != nil:
+			outcome = fmt.Sprintf("failed: %s", result.err)
+		case result.action != "":
+			outcome = result.action
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.app, orDash(result.from), orDash(result.to), orDash(result.node), outcome)
+	}
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().Bool("all", false, "Update every installed CLI")
+}
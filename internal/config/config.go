@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rdaniels6813/cli-manager/internal/util"
+	"github.com/spf13/afero"
+)
+
+// Config user settings for cli-manager, read from ~/.cli-manager/config.json
+// and overridden by CLI_MANAGER_* environment variables
+type Config struct {
+	NodeMirror           string `json:"node_mirror,omitempty"`
+	NodeUnofficialMirror string `json:"node_unofficial_mirror,omitempty"`
+	NodeMirrorUsername   string `json:"node_mirror_username,omitempty"`
+	NodeMirrorPassword   string `json:"node_mirror_password,omitempty"`
+	NodeMirrorToken      string `json:"node_mirror_token,omitempty"`
+	// NodeScheduleURL where the node release schedule is read from, for mirrors & air-gapped networks
+	NodeScheduleURL string `json:"node_schedule_url,omitempty"`
+	// NodeIndexTTL how long the node release index is cached, as a duration like 30m or 24h
+	NodeIndexTTL string `json:"node_index_ttl,omitempty"`
+	// NodePolicy the default policy for choosing node versions: lts, active-lts, maintenance, current or exact
+	NodePolicy string `json:"node_policy,omitempty"`
+	// Generations how many previous versions of each app are kept for rollback
+	Generations string `json:"generations,omitempty"`
+}
+
+const (
+	envNodeMirror           = "CLI_MANAGER_NODE_MIRROR"
+	envNodeUnofficialMirror = "CLI_MANAGER_NODE_UNOFFICIAL_MIRROR"
+	envNodeMirrorUsername   = "CLI_MANAGER_NODE_MIRROR_USERNAME"
+	envNodeMirrorPassword   = "CLI_MANAGER_NODE_MIRROR_PASSWORD"
+	envNodeMirrorToken      = "CLI_MANAGER_NODE_MIRROR_TOKEN"
+	envNodeScheduleURL      = "CLI_MANAGER_NODE_SCHEDULE_URL"
+	envNodeIndexTTL         = "CLI_MANAGER_NODE_INDEX_TTL"
+	envNodePolicy           = "CLI_MANAGER_NODE_POLICY"
+	envGenerations          = "CLI_MANAGER_GENERATIONS"
+)
+
+// GetConfigPath returns the path to the config file
+func GetConfigPath(aos afero.Fs) string {
+	return filepath.Join(util.GetCliManagerFolder(aos), "config.json")
+}
+
+// Load reads the config file and applies any environment overrides
+func Load(aos afero.Fs) (*Config, error) {
+	cfg := &Config{}
+	path := GetConfigPath(aos)
+	f, err := aos.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		defer f.Close()
+		err = json.NewDecoder(f).Decode(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %w", path, err)
+		}
+	}
+	cfg.applyEnv()
+	return cfg, nil
+}
+
+func (c *Config) applyEnv() {
+	setFromEnv(&c.NodeMirror, envNodeMirror)
+	setFromEnv(&c.NodeUnofficialMirror, envNodeUnofficialMirror)
+	setFromEnv(&c.NodeMirrorUsername, envNodeMirrorUsername)
+	setFromEnv(&c.NodeMirrorPassword, envNodeMirrorPassword)
+	setFromEnv(&c.NodeMirrorToken, envNodeMirrorToken)
+	setFromEnv(&c.NodeScheduleURL, envNodeScheduleURL)
+	setFromEnv(&c.NodeIndexTTL, envNodeIndexTTL)
+	setFromEnv(&c.NodePolicy, envNodePolicy)
+	setFromEnv(&c.Generations, envGenerations)
+}
+
+func setFromEnv(value *string, name string) {
+	if env, ok := os.LookupEnv(name); ok {
+		*value = env
+	}
+}
@@ -0,0 +1,185 @@
+package gitsource
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/rdaniels6813/cli-manager/internal/npmrange"
+)
+
+// Resolved the commit a source points at
+type Resolved struct {
+	Commit string
+	// Tag the tag picked for #semver: sources
+	Tag string
+	// ref what is fetched to read files at the commit
+	ref string
+}
+
+// Resolver runs git to resolve sources
+type Resolver struct {
+	// Token returns a GitHub token, only asked for when GitHub refuses an anonymous request
+	Token func() (string, error)
+	token string
+}
+
+// Resolve finds the commit the source's ref, semver range or default branch points at
+func (r *Resolver) Resolve(source *Source) (*Resolved, error) {
+	output, err := r.git(source, "", "ls-remote", source.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list the refs of %s: %w", source.URL, err)
+	}
+	refs := parseRefs(output)
+	switch {
+	case source.Semver != "":
+		return resolveSemver(source, refs)
+	case source.Ref == "":
+		if commit, ok := refs["HEAD"]; ok {
+			return &Resolved{Commit: commit, ref: "HEAD"}, nil
+		}
+		return nil, fmt.Errorf("%s has no default branch", source.URL)
+	}
+	for _, ref := range []string{"refs/heads/" + source.Ref, "refs/tags/" + source.Ref, source.Ref} {
+		if commit, ok := refs[ref]; ok {
+			return &Resolved{Commit: commit, ref: ref}, nil
+		}
+	}
+	if source.IsCommit() {
+		return &Resolved{Commit: strings.ToLower(source.Ref), ref: source.Ref}, nil
+	}
+	return nil, fmt.Errorf("%s has no branch, tag or commit named %s", source.URL, source.Ref)
+}
+
+// ReadFile returns a file of the source's package at the resolved commit, fetching no more than needed to read it
+func (r *Resolver) ReadFile(source *Source, resolved *Resolved, name string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "cli-manager-git")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	fetch, target := []string{"fetch", "-q", "--depth", "1", "--filter=blob:none", "origin", resolved.ref}, "FETCH_HEAD"
+	if len(resolved.Commit) < 40 {
+		// abbreviated commits cannot be fetched directly, so the history of every branch is fetched instead
+		fetch, target = []string{"fetch", "-q", "--filter=blob:none", "origin"}, resolved.Commit
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"remote", "add", "origin", source.URL}, fetch} {
+		_, err = r.git(source, dir, args...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch %s from %s: %w", resolved.Commit, source.URL, err)
+		}
+	}
+	commit, err := r.git(source, dir, "rev-parse", target+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%s has no commit %s: %w", source.URL, resolved.Commit, err)
+	}
+	resolved.Commit = strings.TrimSpace(string(commit))
+	data, err := r.git(source, dir, "show", target+":"+path.Join(source.Path, name))
+	if err != nil {
+		return nil, fmt.Errorf("%s at %s has no %s: %w", source.URL, resolved.Commit, path.Join(source.Path, name), err)
+	}
+	return data, nil
+}
+
+// git runs a git command without prompting for credentials, retrying GitHub requests with a token
+func (r *Resolver) git(source *Source, dir string, args ...string) ([]byte, error) {
+	output, err := r.run(dir, args...)
+	if err == nil || source.Host != GitHub || r.Token == nil {
+		return output, err
+	}
+	if r.token == "" {
+		token, tokenErr := r.Token()
+		if tokenErr != nil || token == "" {
+			return output, err
+		}
+		r.token = token
+	}
+	return r.run(dir, args...)
+}
+
+func (r *Resolver) run(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if r.token != "" {
+		// the token goes through the environment rather than a -c argument, which other users could read
+		credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + r.token))
+		cmd.Env = append(cmd.Env, configEnv("http.https://github.com/.extraheader", "AUTHORIZATION: basic "+credentials)...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// configEnv returns the environment setting a git config value, after any set the same way by the caller
+func configEnv(key string, value string) []string {
+	count, err := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	if err != nil || count < 0 {
+		count = 0
+	}
+	return []string{
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", count, key),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", count, value),
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", count+1),
+	}
+}
+
+// parseRefs reads git ls-remote output into commits keyed by ref, using the commit of annotated tags
+func parseRefs(output []byte) map[string]string {
+	refs := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		commit, ref, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+		if peeled := strings.TrimSuffix(ref, "^{}"); peeled != ref {
+			refs[peeled] = commit
+		} else if _, ok := refs[ref]; !ok {
+			refs[ref] = commit
+		}
+	}
+	return refs
+}
+
+// resolveSemver picks the highest tag matching the source's semver range
+func resolveSemver(source *Source, refs map[string]string) (*Resolved, error) {
+	versionRange, err := npmrange.Parse(source.Semver)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing the semver range of %s: %w", source.URL, err)
+	}
+	var resolved *Resolved
+	var highest semver.Version
+	for ref, commit := range refs {
+		tag := strings.TrimPrefix(ref, "refs/tags/")
+		if tag == ref {
+			continue
+		}
+		version, err := semver.ParseTolerant(tag)
+		if err != nil || !versionRange.Contains(version) {
+			continue
+		}
+		if resolved == nil || version.GT(highest) {
+			resolved = &Resolved{Commit: commit, Tag: tag, ref: ref}
+			highest = version
+		}
+	}
+	if resolved == nil {
+		return nil, fmt.Errorf("%s has no tag matching %s", source.URL, source.Semver)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,156 @@
+package gitsource_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/gitsource"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRepo a local repository holding a package in packages/cli
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := &testRepo{t: t, dir: t.TempDir()}
+	repo.git("init", "-q", "-b", "main")
+	return repo
+}
+
+func (r *testRepo) git(args ...string) string {
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = r.dir
+	output, err := cmd.CombinedOutput()
+	assert.Nil(r.t, err, string(output))
+	return strings.TrimSpace(string(output))
+}
+
+// commit writes the package version & returns the commit
+func (r *testRepo) commit(version string) string {
+	dir := filepath.Join(r.dir, "packages", "cli")
+	assert.Nil(r.t, os.MkdirAll(dir, 0700))
+	pkg := fmt.Sprintf(`{"name": "@corp/cli", "version": %q, "bin": {"corp": "bin/corp.js"}}`, version)
+	assert.Nil(r.t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkg), 0600))
+	r.git("add", "-A")
+	r.git("commit", "-q", "-m", version)
+	return r.git("rev-parse", "HEAD")
+}
+
+func (r *testRepo) spec(committish string) string {
+	return "git+file://" + filepath.ToSlash(r.dir) + committish
+}
+
+func TestResolve(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit("1.0.0")
+	repo.git("tag", "-a", "v1.0.0", "-m", "v1.0.0")
+	second := repo.commit("1.1.0")
+	repo.git("tag", "v1.1.0")
+	third := repo.commit("2.0.0")
+	repo.git("tag", "v2.0.0")
+	repo.git("checkout", "-q", "-b", "next", first)
+	next := repo.commit("3.0.0-beta")
+	repo.git("checkout", "-q", "main")
+	resolver := &gitsource.Resolver{}
+
+	for committish, expected := range map[string]*gitsource.Resolved{
+		"":                  {Commit: third},
+		"#next":             {Commit: next},
+		"#v1.0.0":           {Commit: first},
+		"#semver:^1":        {Commit: second, Tag: "v1.1.0"},
+		"#semver:<1.1":      {Commit: first, Tag: "v1.0.0"},
+		"#" + first[:10]:    {Commit: strings.ToLower(first[:10])},
+		"#main::path:other": {Commit: third},
+	} {
+		source, ok := gitsource.Parse(repo.spec(committish))
+		assert.True(t, ok)
+		resolved, err := resolver.Resolve(source)
+		assert.Nil(t, err, committish)
+		assert.Equal(t, expected.Commit, resolved.Commit, committish)
+		assert.Equal(t, expected.Tag, resolved.Tag, committish)
+	}
+
+	for committish, message := range map[string]string{
+		"#missing":     "has no branch, tag or commit named missing",
+		"#semver:^4.0": "has no tag matching ^4.0",
+	} {
+		source, _ := gitsource.Parse(repo.spec(committish))
+		_, err := resolver.Resolve(source)
+		assert.ErrorContains(t, err, message, committish)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit("1.0.0")
+	repo.git("tag", "v1.0.0")
+	repo.commit("1.1.0")
+	resolver := &gitsource.Resolver{}
+	source, _ := gitsource.Parse(repo.spec("#semver:~1.0.0::path:packages/cli"))
+	resolved, err := resolver.Resolve(source)
+	assert.Nil(t, err)
+
+	data, err := resolver.ReadFile(source, resolved, "package.json")
+
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"version": "1.0.0"`)
+	assert.Equal(t, first, resolved.Commit)
+	_, err = resolver.ReadFile(source, resolved, "missing.json")
+	assert.ErrorContains(t, err, "has no packages/cli/missing.json")
+}
+
+func TestReadFileAtShortCommit(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit("1.0.0")
+	repo.commit("1.1.0")
+	resolver := &gitsource.Resolver{}
+	source, _ := gitsource.Parse(repo.spec("#" + first[:8] + "::path:packages/cli"))
+	resolved, err := resolver.Resolve(source)
+	assert.Nil(t, err)
+
+	data, err := resolver.ReadFile(source, resolved, "package.json")
+
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"version": "1.0.0"`)
+	assert.Equal(t, first, resolved.Commit)
+}
+
+func TestResolveWithGitHubToken(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake git is a shell script")
+	}
+	// a fake git refusing anonymous requests, recording how the token was passed
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	script := `#!/bin/sh
+echo "$* | ${GIT_CONFIG_COUNT:-none} ${GIT_CONFIG_KEY_0:-none}" >> "` + calls + `"
+[ -n "$GIT_CONFIG_COUNT" ] || exit 128
+printf '0123456789012345678901234567890123456789\tHEAD\n'
+`
+	assert.Nil(t, os.WriteFile(filepath.Join(bin, "git"), []byte(script), 0700))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("GIT_CONFIG_COUNT", "")
+	resolver := &gitsource.Resolver{Token: func() (string, error) { return "secret", nil }}
+	source, _ := gitsource.Parse("github:owner/repo")
+
+	resolved, err := resolver.Resolve(source)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789012345678901234567890123456789", resolved.Commit)
+	data, err := os.ReadFile(calls)
+	assert.Nil(t, err)
+	assert.Equal(t, "ls-remote https://github.com/owner/repo.git | none none\n"+
+		"ls-remote https://github.com/owner/repo.git | 1 http.https://github.com/.extraheader\n", string(data))
+	assert.NotContains(t, string(data), "secret")
+}
@@ -0,0 +1,93 @@
+// Package gitsource resolves npm git dependency specs, like github:owner/repo#semver:^1.2, to commits
+package gitsource
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Hosts with a shorthand spec, like gitlab:owner/repo
+const (
+	GitHub    = "github"
+	GitLab    = "gitlab"
+	Bitbucket = "bitbucket"
+)
+
+var hostURLs = map[string]string{
+	GitHub:    "https://github.com/%s.git",
+	GitLab:    "https://gitlab.com/%s.git",
+	Bitbucket: "https://bitbucket.org/%s.git",
+}
+
+var (
+	shorthandPattern = regexp.MustCompile(`^(?:(github|gitlab|bitbucket):)?([^@./:#][^/:#]*/[^/:#]+)$`)
+	commitPattern    = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+	gitSchemes       = []string{"git+https://", "git+http://", "git+ssh://", "git+file://", "git://"}
+)
+
+// Source a package in a git repository
+type Source struct {
+	// Host the shorthand host, empty for git URLs
+	Host string
+	// Repo owner/repo for shorthand sources
+	Repo string
+	// URL the URL the repository is cloned from
+	URL string
+	// Ref the branch, tag or commit, empty for the default branch
+	Ref string
+	// Semver the range of tags to pick the highest from, given as #semver:<range>
+	Semver string
+	// Path the folder of the package within the repository, given as ::path:<dir>
+	Path string
+
+	base string
+}
+
+// Parse reads a git spec, returning false when the spec is not one
+func Parse(spec string) (*Source, bool) {
+	base, committish, _ := strings.Cut(spec, "#")
+	source := &Source{base: base}
+	if match := shorthandPattern.FindStringSubmatch(base); match != nil {
+		source.Host = match[1]
+		if source.Host == "" {
+			source.Host = GitHub
+		}
+		source.Repo = strings.TrimSuffix(match[2], ".git")
+		source.URL = fmt.Sprintf(hostURLs[source.Host], source.Repo)
+	} else {
+		for _, scheme := range gitSchemes {
+			if strings.HasPrefix(base, scheme) {
+				source.URL = strings.TrimPrefix(base, "git+")
+			}
+		}
+		if source.URL == "" {
+			return nil, false
+		}
+	}
+	for _, part := range strings.Split(committish, "::") {
+		switch {
+		case strings.HasPrefix(part, "semver:"):
+			source.Semver = strings.TrimPrefix(part, "semver:")
+		case strings.HasPrefix(part, "path:"):
+			source.Path = strings.Trim(strings.TrimPrefix(part, "path:"), "/")
+		default:
+			source.Ref = part
+		}
+	}
+	return source, true
+}
+
+// Pinned returns the spec installing the package from the commit
+func (s *Source) Pinned(commit string) string {
+	spec := s.base + "#" + commit
+	if s.Path != "" {
+		spec += "::path:" + s.Path
+	}
+	return spec
+}
+
+// IsCommit reports whether the ref names a commit rather than a branch or tag
+func (s *Source) IsCommit() bool {
+	return commitPattern.MatchString(s.Ref)
+}
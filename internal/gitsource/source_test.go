@@ -0,0 +1,58 @@
+package gitsource_test
+
+import (
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/gitsource"
+	"github.com/stretchr/testify/assert"
+)
+
+var sourceTests = []struct {
+	spec   string
+	source *gitsource.Source
+	pinned string
+}{
+	{"rdaniels6813/cli-manager", &gitsource.Source{Host: "github", Repo: "rdaniels6813/cli-manager",
+		URL: "https://github.com/rdaniels6813/cli-manager.git"}, "rdaniels6813/cli-manager#abc1234"},
+	{"github:rdaniels6813/cli-manager#main", &gitsource.Source{Host: "github", Repo: "rdaniels6813/cli-manager",
+		URL: "https://github.com/rdaniels6813/cli-manager.git", Ref: "main"}, "github:rdaniels6813/cli-manager#abc1234"},
+	{"gitlab:corp/tools#semver:^1.2::path:packages/cli", &gitsource.Source{Host: "gitlab", Repo: "corp/tools",
+		URL: "https://gitlab.com/corp/tools.git", Semver: "^1.2", Path: "packages/cli"},
+		"gitlab:corp/tools#abc1234::path:packages/cli"},
+	{"bitbucket:corp/tools.git#v1.0.0", &gitsource.Source{Host: "bitbucket", Repo: "corp/tools",
+		URL: "https://bitbucket.org/corp/tools.git", Ref: "v1.0.0"}, "bitbucket:corp/tools.git#abc1234"},
+	{"git+https://git.example.com/corp/tools.git#main::path:/cli/", &gitsource.Source{
+		URL: "https://git.example.com/corp/tools.git", Ref: "main", Path: "cli"},
+		"git+https://git.example.com/corp/tools.git#abc1234::path:cli"},
+	{"git+ssh://git@git.example.com/corp/tools.git#0123abcd", &gitsource.Source{
+		URL: "ssh://git@git.example.com/corp/tools.git", Ref: "0123abcd"},
+		"git+ssh://git@git.example.com/corp/tools.git#abc1234"},
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range sourceTests {
+		source, ok := gitsource.Parse(test.spec)
+		assert.True(t, ok, test.spec)
+		assert.Equal(t, test.source.Host, source.Host, test.spec)
+		assert.Equal(t, test.source.Repo, source.Repo, test.spec)
+		assert.Equal(t, test.source.URL, source.URL, test.spec)
+		assert.Equal(t, test.source.Ref, source.Ref, test.spec)
+		assert.Equal(t, test.source.Semver, source.Semver, test.spec)
+		assert.Equal(t, test.source.Path, source.Path, test.spec)
+		assert.Equal(t, test.pinned, source.Pinned("abc1234"), test.spec)
+	}
+}
+
+func TestParseNotGit(t *testing.T) {
+	for _, spec := range []string{"typescript", "@angular/cli@15", "./tools", "/opt/tools", "https://example.com/cli.tgz"} {
+		_, ok := gitsource.Parse(spec)
+		assert.False(t, ok, spec)
+	}
+}
+
+func TestIsCommit(t *testing.T) {
+	source, _ := gitsource.Parse("owner/repo#0123abcd")
+	assert.True(t, source.IsCommit())
+	source, _ = gitsource.Parse("owner/repo#main")
+	assert.False(t, source.IsCommit())
+}
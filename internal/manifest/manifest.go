@@ -0,0 +1,128 @@
+// Package manifest reads & writes cli-manager.yaml, a checked-in list of the CLIs a team uses
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rdaniels6813/cli-manager/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile the manifest file name sync, export & import use by default
+const DefaultFile = "cli-manager.yaml"
+
+// Manifest the CLIs to install
+type Manifest struct {
+	Apps []App `yaml:"apps"`
+}
+
+// App a CLI in the manifest
+type App struct {
+	// Package the npm package name, or a spec npm can install such as owner/repo#branch
+	Package string `yaml:"package"`
+	// Version an npm version range or dist-tag, the latest dist-tag when empty
+	Version string `yaml:"version,omitempty"`
+	// Node the npm style node version range the CLI is pinned to
+	Node string `yaml:"node,omitempty"`
+	// Registry the npm registry the package is installed from
+	Registry string `yaml:"registry,omitempty"`
+	// Aliases extra command names, mapped to the bins they run
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// Spec returns the package spec to pass to npm install
+func (a *App) Spec() string {
+	if a.Version == "" {
+		return a.Package
+	}
+	return fmt.Sprintf("%s@%s", a.Package, a.Version)
+}
+
+// Load reads & validates the manifest at path
+func Load(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	manifest, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// Parse reads & validates a manifest
+func Parse(r io.Reader) (*Manifest, error) {
+	var manifest Manifest
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	err := decoder.Decode(&manifest)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &manifest, manifest.validate()
+}
+
+func (m *Manifest) validate() error {
+	seen := map[string]bool{}
+	aliases := map[string]string{}
+	for i, app := range m.Apps {
+		name := strings.TrimSpace(app.Package)
+		if name == "" {
+			return fmt.Errorf("app %d has no package", i+1)
+		}
+		if seen[name] {
+			return fmt.Errorf("%s is listed more than once", name)
+		}
+		seen[name] = true
+		for alias, bin := range app.Aliases {
+			if !util.IsCommandName(alias) {
+				return fmt.Errorf("the alias %q of %s is not a valid command name", alias, name)
+			}
+			if !util.IsCommandName(bin) {
+				return fmt.Errorf("the alias %s of %s runs %q, which is not a valid command name", alias, name, bin)
+			}
+			if other, ok := aliases[alias]; ok {
+				return fmt.Errorf("the alias %s is used by both %s and %s", alias, other, name)
+			}
+			aliases[alias] = name
+		}
+	}
+	return nil
+}
+
+// Find returns the app for the package name
+func (m *Manifest) Find(name string) (*App, bool) {
+	for i := range m.Apps {
+		if m.Apps[i].Package == name {
+			return &m.Apps[i], true
+		}
+	}
+	return nil, false
+}
+
+// Write encodes the manifest with the apps sorted by package
+func (m *Manifest) Write(w io.Writer) error {
+	sort.Slice(m.Apps, func(i, j int) bool {
+		return m.Apps[i].Package < m.Apps[j].Package
+	})
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err := encoder.Encode(m)
+	if err != nil {
+		return err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,77 @@
+package manifest_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+const teamManifest = `apps:
+  - package: typescript
+    version: ^5.0
+  - package: "@angular/cli"
+    version: next
+    node: ^18
+    registry: https://npm.example.com/
+    aliases:
+      angular: ng
+  - package: rdaniels6813/some-cli#main
+`
+
+func TestParse(t *testing.T) {
+	m, err := manifest.Parse(strings.NewReader(teamManifest))
+
+	assert.Nil(t, err)
+	assert.Len(t, m.Apps, 3)
+	assert.Equal(t, "typescript@^5.0", m.Apps[0].Spec())
+	assert.Equal(t, "@angular/cli@next", m.Apps[1].Spec())
+	assert.Equal(t, "rdaniels6813/some-cli#main", m.Apps[2].Spec())
+	app, ok := m.Find("@angular/cli")
+	assert.True(t, ok)
+	assert.Equal(t, manifest.App{
+		Package:  "@angular/cli",
+		Version:  "next",
+		Node:     "^18",
+		Registry: "https://npm.example.com/",
+		Aliases:  map[string]string{"angular": "ng"},
+	}, *app)
+}
+
+func TestParseEmpty(t *testing.T) {
+	m, err := manifest.Parse(strings.NewReader(""))
+
+	assert.Nil(t, err)
+	assert.Empty(t, m.Apps)
+}
+
+func TestParseErrors(t *testing.T) {
+	for input, expected := range map[string]string{
+		"apps:\n  - version: ^5\n":                                                      "app 1 has no package",
+		"apps:\n  - package: tsc\n  - package: tsc\n":                                   "tsc is listed more than once",
+		"apps:\n  - package: tsc\n    versoin: ^5\n":                                    "field versoin not found",
+		"apps:\n  - {package: a, aliases: {x: a}}\n  - {package: b, aliases: {x: b}}\n": "the alias x is used by both a and b",
+		"apps:\n  - {package: a, aliases: {../../.bashrc: a}}\n":                        "not a valid command name",
+		"apps:\n  - {package: a, aliases: {..: a}}\n":                                   "not a valid command name",
+		"apps:\n  - {package: a, aliases: {x: \"a; rm -rf ~\"}}\n":                      "runs \"a; rm -rf ~\"",
+		"apps:\n  - {package: a, aliases: {\"$(id)\": a}}\n":                            "not a valid command name",
+	} {
+		_, err := manifest.Parse(strings.NewReader(input))
+		assert.ErrorContains(t, err, expected, input)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	m, err := manifest.Parse(strings.NewReader(teamManifest))
+	assert.Nil(t, err)
+	var buf bytes.Buffer
+
+	assert.Nil(t, m.Write(&buf))
+
+	assert.True(t, strings.HasPrefix(buf.String(), "apps:\n  - package: '@angular/cli'\n"), buf.String())
+	written, err := manifest.Parse(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, m, written)
+}
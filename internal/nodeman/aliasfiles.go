@@ -0,0 +1,107 @@
+package nodeman
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rdaniels6813/cli-manager/internal/util"
+	"github.com/spf13/afero"
+)
+
+// Aliases files sourced by shell profiles, named by the shell they are written for
+const (
+	ZshAliasesFile        = "aliases.zsh"
+	BashAliasesFile       = "aliases.bash"
+	PowershellAliasesFile = "aliases.ps1"
+	FishAliasesFile       = "aliases.fish"
+)
+
+// aliasFormats the line defining an alias to a bin in each aliases file
+var aliasFormats = map[string]string{
+	ZshAliasesFile:        "alias %s='cli-manager run %s'\n",
+	BashAliasesFile:       "alias %s='cli-manager run %s'\n",
+	PowershellAliasesFile: "function %s { cli-manager.exe run %s @args }\n",
+	FishAliasesFile:       "function %s; cli-manager run %s $argv; end\n",
+}
+
+// GetAliasesFilePath returns the path of an aliases file, kept up to date with the installed apps
+func GetAliasesFilePath(aos afero.Fs, file string) string {
+	return filepath.Join(util.GetCliManagerFolder(aos), "aliases", file)
+}
+
+// AliasesScript returns the content of an aliases file for the installed apps
+func (m *Manager) AliasesScript(file string) string {
+	return aliasesScript(file, m.loadApps())
+}
+
+// WriteAliasesFiles writes the aliases files for the installed apps
+func (m *Manager) WriteAliasesFiles() error {
+	return m.writeAliasesFiles(m.loadApps())
+}
+
+func (m *Manager) writeAliasesFiles(apps map[string]*CLIApp) error {
+	for _, app := range apps {
+		for _, bin := range app.Bins {
+			if !util.IsCommandName(bin) {
+				fmt.Fprintf(os.Stderr, "Left %q of %s out of the aliases files, it is not a valid command name\n", bin, app.App)
+			}
+		}
+		for alias, bin := range app.Aliases {
+			if !util.IsCommandName(alias) || !util.IsCommandName(bin) {
+				fmt.Fprintf(os.Stderr, "Left the alias %q of %s out of the aliases files, it is not a valid command name\n", alias, app.App)
+			}
+		}
+	}
+	for file := range aliasFormats {
+		path := GetAliasesFilePath(m.os, file)
+		script := aliasesScript(file, apps)
+		if current, err := afero.ReadFile(m.os, path); err == nil && string(current) == script {
+			continue
+		}
+		err := m.os.MkdirAll(filepath.Dir(path), 0700)
+		if err != nil {
+			return err
+		}
+		err = util.WriteFileAtomic(m.os, path, []byte(script), 0600)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aliasesScript defines an alias running every bin through cli-manager, then the user's own aliases,
+// sorted so the file only changes along with the apps. Names that are not plain command names are left out.
+func aliasesScript(file string, apps map[string]*CLIApp) string {
+	bins := []string{}
+	aliases := map[string]string{}
+	for _, app := range apps {
+		for _, bin := range app.Bins {
+			if util.IsCommandName(bin) {
+				bins = append(bins, bin)
+			}
+		}
+		for alias, bin := range app.Aliases {
+			if util.IsCommandName(alias) && util.IsCommandName(bin) {
+				aliases[alias] = bin
+			}
+		}
+	}
+	sort.Strings(bins)
+	names := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	var script strings.Builder
+	for _, bin := range bins {
+		fmt.Fprintf(&script, aliasFormats[file], bin, bin)
+	}
+	for _, alias := range names {
+		fmt.Fprintf(&script, aliasFormats[file], alias, aliases[alias])
+	}
+	return script.String()
+}
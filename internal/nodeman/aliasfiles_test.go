@@ -0,0 +1,35 @@
+package nodeman_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliasesFiles(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	assert.Nil(t, manager.SetAliases("typescript", map[string]string{"ts": "tsc"}))
+
+	data, err := os.ReadFile(nodeman.GetAliasesFilePath(afero.NewOsFs(), nodeman.ZshAliasesFile))
+	assert.Nil(t, err)
+	assert.Equal(t, "alias ng='cli-manager run ng'\n"+
+		"alias tsc='cli-manager run tsc'\n"+
+		"alias tsserver='cli-manager run tsserver'\n"+
+		"alias ts='cli-manager run tsc'\n", string(data))
+	assert.Equal(t, string(data), manager.AliasesScript(nodeman.ZshAliasesFile))
+
+	assert.Nil(t, manager.MarkUninstalled("typescript"))
+	data, err = os.ReadFile(nodeman.GetAliasesFilePath(afero.NewOsFs(), nodeman.PowershellAliasesFile))
+	assert.Nil(t, err)
+	assert.Equal(t, "function ng { cli-manager.exe run ng @args }\n", string(data))
+	data, err = os.ReadFile(nodeman.GetAliasesFilePath(afero.NewOsFs(), nodeman.BashAliasesFile))
+	assert.Nil(t, err)
+	assert.Equal(t, "alias ng='cli-manager run ng'\n", string(data))
+	data, err = os.ReadFile(nodeman.GetAliasesFilePath(afero.NewOsFs(), nodeman.FishAliasesFile))
+	assert.Nil(t, err)
+	assert.Equal(t, "function ng; cli-manager run ng $argv; end\n", string(data))
+}
@@ -0,0 +1,175 @@
+package nodeman
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/rdaniels6813/cli-manager/internal/gitsource"
+	"github.com/rdaniels6813/cli-manager/internal/util"
+)
+
+const stagingSuffix = ".staging"
+
+// packageNamePattern npm's rules for new package names, which keep a name to one folder, or two when scoped
+var packageNamePattern = regexp.MustCompile(`^(?:@[a-z0-9-~][a-z0-9-._~]*/)?[a-z0-9-~][a-z0-9-._~]*$`)
+
+// GetAppPrefix returns the npm prefix an app version is installed into
+func (m *Manager) GetAppPrefix(name string, version string) string {
+	if version == "" {
+		version = "current"
+	}
+	return filepath.Join(m.getAppsBaseFolder(), filepath.FromSlash(name), version)
+}
+
+// InstallApp installs the package into its own prefix using the node version, so apps never share
+// dependencies. The previous install of the same version is only replaced once npm succeeds.
+// Git sources are installed from the commit they resolved to when viewed, linked folders are symlinked.
+func (m *Manager) InstallApp(node Node, installName string, pkg *NpmViewResponse) (string, error) {
+	version := pkg.Version
+	args := []string{}
+	if localPath, link, ok := parseLocalSpec(installName); ok {
+		installName = localPath
+		if link {
+			version = linkVersion
+			args = append(args, "--install-links=false")
+		}
+	} else if source, ok := gitsource.Parse(installName); ok && pkg.Commit != "" {
+		installName = source.Pinned(pkg.Commit)
+		version = fmt.Sprintf("%s-%s", version, shortCommit(pkg.Commit))
+	}
+	err := validatePackage(pkg)
+	if err != nil {
+		return "", err
+	}
+	prefix := m.GetAppPrefix(pkg.Name, version)
+	if !isWithin(m.getAppsBaseFolder(), prefix) {
+		return "", fmt.Errorf("The install folder of %s %s is outside of %s", pkg.Name, version, m.getAppsBaseFolder())
+	}
+	staging := prefix + stagingSuffix
+	err = m.os.RemoveAll(staging)
+	if err != nil {
+		return "", err
+	}
+	err = m.os.MkdirAll(staging, 0700)
+	if err != nil {
+		return "", err
+	}
+	err = node.Npm(append([]string{"install", "-g", "--prefix", staging, installName}, args...)...)
+	if err != nil {
+		m.os.RemoveAll(staging)
+		return "", err
+	}
+	err = m.os.RemoveAll(prefix)
+	if err != nil {
+		return "", err
+	}
+	err = m.os.Rename(staging, prefix)
+	if err != nil {
+		return "", err
+	}
+	return prefix, nil
+}
+
+// validatePackage checks the name & version of a package are safe to build its install folder from,
+// since packages read from folders, tarballs and git repositories are not checked by a registry
+func validatePackage(pkg *NpmViewResponse) error {
+	if len(pkg.Name) > 214 || !packageNamePattern.MatchString(pkg.Name) {
+		return fmt.Errorf("%q is not a valid npm package name", pkg.Name)
+	}
+	_, err := semver.Parse(pkg.Version)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid version of %s: %w", pkg.Version, pkg.Name, err)
+	}
+	return nil
+}
+
+// isWithin reports whether the path is inside the folder once cleaned
+func isWithin(folder string, path string) bool {
+	rel, err := filepath.Rel(folder, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// RemoveAppPrefix deletes an app's install prefix along with the folders left empty by it
+func (m *Manager) RemoveAppPrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	err := m.os.RemoveAll(prefix)
+	if err != nil {
+		return err
+	}
+	appsBaseFolder := m.getAppsBaseFolder()
+	for dir := filepath.Dir(prefix); dir != appsBaseFolder && len(dir) > len(appsBaseFolder); dir = filepath.Dir(dir) {
+		if empty, err := m.isEmptyDir(dir); err != nil || !empty {
+			break
+		}
+		err = m.os.Remove(dir)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appBinPath returns the folder npm links the bins of a prefix into
+func appBinPath(prefix string) string {
+	if runtime.GOOS == windows {
+		return prefix
+	}
+	return filepath.Join(prefix, "bin")
+}
+
+// BinPath returns the folder holding the app's bins
+func (a *CLIApp) BinPath() string {
+	if a.Prefix != "" {
+		return appBinPath(a.Prefix)
+	}
+	return a.Path
+}
+
+// HasBin reports whether bin is one of the app's commands
+func (a *CLIApp) HasBin(bin string) bool {
+	for _, b := range a.Bins {
+		if b == bin {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigureNode points npm at the registry the app is installed from
+func (a *CLIApp) ConfigureNode(node Node) Node {
+	if a.Registry == "" {
+		return node
+	}
+	return node.WithNpmConfig("registry", a.Registry)
+}
+
+func (m *Manager) isEmptyDir(dir string) (bool, error) {
+	f, err := m.os.Open(dir)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(1)
+	if len(names) == 0 && err != nil {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *Manager) getAppsBaseFolder() string {
+	appsFolder := filepath.Join(util.GetCliManagerFolder(m.os), "apps")
+	if _, err := m.os.Stat(appsFolder); os.IsNotExist(err) {
+		err = m.os.MkdirAll(appsFolder, 0700)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+	return appsFolder
+}
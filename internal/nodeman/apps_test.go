@@ -0,0 +1,105 @@
+package nodeman_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+// npmNode fakes npm installs by writing the package name into the prefix
+type npmNode struct {
+	t   *testing.T
+	err error
+}
+
+func (n *npmNode) Node(args ...string) error {
+	return nil
+}
+
+func (n *npmNode) Npm(args ...string) error {
+	if n.err != nil {
+		return n.err
+	}
+	assert.Equal(n.t, []string{"install", "-g", "--prefix"}, args[:3])
+	return os.WriteFile(filepath.Join(args[3], "installed"), []byte(args[4]), 0600)
+}
+
+func (n *npmNode) NpmView(packageString string) (*nodeman.NpmViewResponse, error) {
+	return nil, nil
+}
+
+func (n *npmNode) BinPath() string {
+	return ""
+}
+
+func (n *npmNode) WithNpmConfig(key string, value string) nodeman.Node {
+	return n
+}
+
+func TestInstallApp(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	prefix, err := manager.InstallApp(&npmNode{t: t}, "@angular/cli@15", &nodeman.NpmViewResponse{Name: "@angular/cli", Version: "15.2.0"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(home, ".cli-manager", "apps", "@angular", "cli", "15.2.0"), prefix)
+	content, err := os.ReadFile(filepath.Join(prefix, "installed"))
+	assert.Nil(t, err)
+	assert.Equal(t, "@angular/cli@15", string(content))
+}
+
+func TestInstallAppKeepsPreviousInstallOnFailure(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager := nodeman.NewManager(afero.NewOsFs())
+	pkg := &nodeman.NpmViewResponse{Name: "typescript", Version: "4.9.5"}
+	prefix, err := manager.InstallApp(&npmNode{t: t}, "typescript", pkg)
+	assert.Nil(t, err)
+
+	_, err = manager.InstallApp(&npmNode{t: t, err: errors.New("npm failed")}, "typescript", pkg)
+
+	assert.EqualError(t, err, "npm failed")
+	assert.FileExists(t, filepath.Join(prefix, "installed"))
+	assert.NoDirExists(t, prefix+".staging")
+}
+
+func TestRemoveAppPrefix(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	node := &npmNode{t: t}
+	first, err := manager.InstallApp(node, "@angular/cli@14", &nodeman.NpmViewResponse{Name: "@angular/cli", Version: "14.2.10"})
+	assert.Nil(t, err)
+	second, err := manager.InstallApp(node, "@angular/cli@15", &nodeman.NpmViewResponse{Name: "@angular/cli", Version: "15.2.0"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, manager.RemoveAppPrefix(first))
+	assert.NoDirExists(t, first)
+	assert.DirExists(t, second)
+
+	assert.Nil(t, manager.RemoveAppPrefix(second))
+	assert.NoDirExists(t, filepath.Join(home, ".cli-manager", "apps", "@angular"))
+	assert.DirExists(t, filepath.Join(home, ".cli-manager", "apps"))
+}
+
+func TestMarkInstalledRefusesBinCollisions(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	err := manager.MarkInstalled(&nodeman.CLIApp{App: "ts-fork", InstallName: "ts-fork"}, map[string]string{"tsc": "bin/tsc"})
+	assert.ErrorContains(t, err, "its tsc command is already provided by typescript")
+
+	err = manager.MarkInstalled(&nodeman.CLIApp{App: "typescript", Prefix: "/prefix"}, map[string]string{"tsc": "bin/tsc"})
+	assert.Nil(t, err)
+	path, err := manager.GetCommandPath("tsc")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join("/prefix", "bin", "tsc"), path)
+	_, err = manager.GetCommandPath("tsserver")
+	assert.ErrorContains(t, err, "tsserver is not installed")
+}
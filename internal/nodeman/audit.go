@@ -0,0 +1,79 @@
+package nodeman
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NodeAudit the security status of a node version used by installed apps
+type NodeAudit struct {
+	Version string
+	Apps    []string
+	// SecurityRelease the newest release of the same major with security fixes, empty when there is none
+	SecurityRelease string
+	// Latest the newest release of the same major, which apps can be moved to
+	Latest string
+	Phase  string
+	End    string
+}
+
+// EndOfLife reports whether the major version no longer receives fixes
+func (a *NodeAudit) EndOfLife() bool {
+	return a.Phase == PhaseEndOfLife
+}
+
+// Vulnerable reports whether a newer release of the same major contains security fixes
+func (a *NodeAudit) Vulnerable() bool {
+	return a.SecurityRelease != ""
+}
+
+// AuditNodes checks every node version installed apps use for newer security releases
+// of the same major and for majors that are past their end-of-life date
+func (m *Manager) AuditNodes() ([]*NodeAudit, error) {
+	releases, err := getNodeReleases(m.dist)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := getNodeSchedule(m.dist)
+	if err != nil {
+		return nil, err
+	}
+	at := now()
+	result := []*NodeAudit{}
+	// only the current installs are audited, as they are what gets moved to a newer release
+	for version, apps := range m.getNodeUsage(false) {
+		current, err := parseSemver(version)
+		if err != nil {
+			continue
+		}
+		audit := &NodeAudit{Version: current.String(), Apps: apps}
+		latest, security := current, current
+		for _, release := range *releases {
+			candidate, err := parseSemver(release.Version)
+			if err != nil || candidate.Major != current.Major {
+				continue
+			}
+			if candidate.GT(latest) {
+				latest = candidate
+			}
+			if release.Security && candidate.GT(security) {
+				security = candidate
+			}
+		}
+		audit.Latest = latest.String()
+		if security.GT(current) {
+			audit.SecurityRelease = security.String()
+		}
+		if line, ok := schedule[fmt.Sprintf("v%d", current.Major)]; ok {
+			audit.Phase = line.phase(at)
+			audit.End = line.End
+		}
+		result = append(result, audit)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, _ := parseSemver(result[i].Version)
+		b, _ := parseSemver(result[j].Version)
+		return a.LT(b)
+	})
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package nodeman
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditNodes(t *testing.T) {
+	setNow(t, "2021-06-01")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	nodeFolder := filepath.Join(home, ".cli-manager", "node")
+	manager := NewManager(afero.NewOsFs(), WithDist(NewDist(&fixtureClient{t: t})))
+	for _, app := range []*CLIApp{
+		{App: "@angular/cli", Path: filepath.Join(nodeFolder, "12.18.0", "bin")},
+		{App: "typescript", Path: filepath.Join(nodeFolder, "10.22.1", "bin")},
+		{App: "yarn", Path: filepath.Join(nodeFolder, "14.15.0", "bin")},
+		{App: "gh", Path: "/usr/local/bin"},
+	} {
+		assert.Nil(t, manager.MarkInstalled(app, map[string]string{app.App: "bin"}))
+	}
+	// a rollback snapshot on an older node version is not audited, only kept from being removed
+	apps := manager.loadApps()
+	apps["typescript"].Previous = []*CLIApp{{App: "typescript", Path: filepath.Join(nodeFolder, "8.17.0", "bin")}}
+	assert.Nil(t, manager.saveApps(apps))
+	assert.Equal(t, []string{"typescript"}, manager.getNodeUsage(true)["8.17.0"])
+
+	audits, err := manager.AuditNodes()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*NodeAudit{
+		{Version: "10.22.1", Apps: []string{"typescript"}, Latest: "10.23.0", Phase: PhaseEndOfLife, End: "2021-04-30"},
+		{Version: "12.18.0", Apps: []string{"@angular/cli"}, SecurityRelease: "12.18.4", Latest: "12.19.0",
+			Phase: PhaseMaintenance, End: "2022-04-30"},
+		{Version: "14.15.0", Apps: []string{"yarn"}, Latest: "14.15.0", Phase: PhaseActiveLTS, End: "2023-04-30"},
+	}, audits)
+	assert.True(t, audits[0].EndOfLife())
+	assert.False(t, audits[0].Vulnerable())
+	assert.True(t, audits[1].Vulnerable())
+}
@@ -0,0 +1,173 @@
+package nodeman
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck
+)
+
+const shasumsFile = "SHASUMS256.txt"
+
+// releaseKeys the armored keys of the node releasers, one file per key as published in nodejs/release-keys
+//
+//go:embed release-keys
+var releaseKeys embed.FS
+
+// verifyNodeArchive checks the downloaded archive against the SHASUMS256.txt published with the release,
+// and optionally the signature of SHASUMS256.txt against the release keyring.
+// The archive is removed if it cannot be verified.
+func (m *Manager) verifyNodeArchive(version string, archivePath string) error {
+	err := m.checkNodeArchive(version, archivePath)
+	if err != nil {
+		removeErr := os.Remove(archivePath)
+		if removeErr != nil && !os.IsNotExist(removeErr) {
+			return fmt.Errorf("%w (failed to remove archive: %s)", err, removeErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *Manager) checkNodeArchive(version string, archivePath string) error {
+	shasumsURL := fmt.Sprintf("%s/%s", m.getNodeReleaseURL(version), shasumsFile)
+	shasums, err := m.fetch(shasumsURL)
+	if err != nil {
+		return fmt.Errorf("Failed to download checksums for node v%s: %w", version, err)
+	}
+	if m.releaseKeyring != "" {
+		if m.dist.isUnofficial() {
+			return fmt.Errorf("Signatures are not published for unofficial builds of node v%s, "+
+				"install without signature verification to use them", version)
+		}
+		signature, err := m.fetch(shasumsURL + ".asc")
+		if err != nil {
+			return fmt.Errorf("Failed to download checksum signature for node v%s: %w", version, err)
+		}
+		err = verifyShasumsSignature(shasums, signature, m.releaseKeyring)
+		if err != nil {
+			return err
+		}
+	}
+	archiveName := filepath.Base(archivePath)
+	expected, ok := parseShasums(shasums)[archiveName]
+	if !ok {
+		return fmt.Errorf("No checksum published for %s", archiveName)
+	}
+	actual, err := sha256File(archivePath)
+	if err != nil {
+		return fmt.Errorf("Failed to compute checksum for %s: %w", archiveName, err)
+	}
+	if !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("Checksum mismatch for %s: expected %s, got %s", archiveName, expected, actual)
+	}
+	return nil
+}
+
+func (m *Manager) fetch(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.dist.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseShasums parses the `<sha256>  <filename>` lines of a SHASUMS256.txt file
+func parseShasums(data []byte) map[string]string {
+	result := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		result[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+	return result
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func verifyShasumsSignature(shasums []byte, signature []byte, keyringPath string) error {
+	keyring, err := loadReleaseKeyring(keyringPath)
+	if err != nil {
+		return err
+	}
+	_, err = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(shasums), bytes.NewReader(signature))
+	if err != nil {
+		return fmt.Errorf("Invalid signature for %s: %w", shasumsFile, err)
+	}
+	return nil
+}
+
+// loadReleaseKeyring reads the armored keyring at keyringPath when it exists,
+// otherwise the node release keys built into cli-manager
+func loadReleaseKeyring(keyringPath string) (openpgp.EntityList, error) {
+	keyringFile, err := os.Open(keyringPath)
+	if err == nil {
+		defer keyringFile.Close()
+		keyring, err := openpgp.ReadArmoredKeyRing(keyringFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read node release keyring %s: %w", keyringPath, err)
+		}
+		return keyring, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("Failed to open node release keyring: %w", err)
+	}
+	entries, err := releaseKeys.ReadDir("release-keys")
+	if err != nil {
+		return nil, err
+	}
+	keyring := openpgp.EntityList{}
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".asc" {
+			continue
+		}
+		key, err := releaseKeys.Open("release-keys/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		entities, err := openpgp.ReadArmoredKeyRing(key)
+		key.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read node release key %s: %w", entry.Name(), err)
+		}
+		keyring = append(keyring, entities...)
+	}
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("No node release keys are built into this cli-manager, "+
+			"add the keyring at %s to verify signatures", keyringPath)
+	}
+	return keyring, nil
+}
@@ -0,0 +1,186 @@
+package nodeman_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"       //nolint:staticcheck
+	"golang.org/x/crypto/openpgp/armor" //nolint:staticcheck
+)
+
+var archiveContent = []byte("not really a node archive")
+
+type releaseClientMock struct {
+	archiveName string
+	shasums     func(archiveName string) string
+	signature   []byte
+}
+
+func (c *releaseClientMock) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	switch {
+	case strings.HasSuffix(req.URL.Path, "SHASUMS256.txt"):
+		body = []byte(c.shasums(c.archiveName))
+	case strings.HasSuffix(req.URL.Path, "SHASUMS256.txt.asc"):
+		body = c.signature
+	default:
+		c.archiveName = path.Base(req.URL.Path)
+		body = archiveContent
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+func checksumOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func wrongShasums(archiveName string) string {
+	return fmt.Sprintf("%s  %s\n", checksumOf([]byte("something else")), archiveName)
+}
+
+func archivesIn(t *testing.T, home string) []string {
+	entries, err := os.ReadDir(filepath.Join(home, ".cli-manager", "node"))
+	assert.Nil(t, err)
+	result := []string{}
+	for _, entry := range entries {
+		result = append(result, entry.Name())
+	}
+	return result
+}
+
+func TestGetNodeChecksumMismatchRemovesArchive(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	client := &releaseClientMock{shasums: wrongShasums}
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(nodeman.NewDist(client)))
+
+	_, err := manager.GetNode("18.14.0")
+
+	assert.ErrorContains(t, err, "Checksum mismatch")
+	assert.Empty(t, archivesIn(t, home))
+}
+
+func TestGetNodeMissingChecksumRemovesArchive(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	client := &releaseClientMock{shasums: func(string) string { return "" }}
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(nodeman.NewDist(client)))
+
+	_, err := manager.GetNode("18.14.0")
+
+	assert.ErrorContains(t, err, "No checksum published")
+	assert.Empty(t, archivesIn(t, home))
+}
+
+func signedShasumsFixture(t *testing.T, signer *openpgp.Entity, trusted *openpgp.Entity) (string, []byte) {
+	keyringPath := filepath.Join(t.TempDir(), "keys.asc")
+	keyring, err := os.Create(keyringPath)
+	assert.Nil(t, err)
+	w, err := armor.Encode(keyring, openpgp.PublicKeyType, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, trusted.Serialize(w))
+	assert.Nil(t, w.Close())
+	assert.Nil(t, keyring.Close())
+
+	var signature bytes.Buffer
+	shasums := wrongShasums(fmt.Sprintf("node-v18.14.0-%s", "archive"))
+	assert.Nil(t, openpgp.ArmoredDetachSign(&signature, signer, strings.NewReader(shasums), nil))
+	return keyringPath, signature.Bytes()
+}
+
+func TestGetNodeInvalidSignatureRemovesArchive(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	trusted, err := openpgp.NewEntity("Node Release", "", "release@example.com", nil)
+	assert.Nil(t, err)
+	attacker, err := openpgp.NewEntity("Attacker", "", "attacker@example.com", nil)
+	assert.Nil(t, err)
+	keyringPath, signature := signedShasumsFixture(t, attacker, trusted)
+	client := &releaseClientMock{
+		shasums:   func(string) string { return wrongShasums(fmt.Sprintf("node-v18.14.0-%s", "archive")) },
+		signature: signature,
+	}
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(nodeman.NewDist(client)), nodeman.WithReleaseKeyring(keyringPath))
+
+	_, err = manager.GetNode("18.14.0")
+
+	assert.ErrorContains(t, err, "Invalid signature")
+	assert.Empty(t, archivesIn(t, home))
+}
+
+func TestGetNodeValidSignatureStillChecksArchive(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	trusted, err := openpgp.NewEntity("Node Release", "", "release@example.com", nil)
+	assert.Nil(t, err)
+	keyringPath, signature := signedShasumsFixture(t, trusted, trusted)
+	client := &releaseClientMock{
+		shasums:   func(string) string { return wrongShasums(fmt.Sprintf("node-v18.14.0-%s", "archive")) },
+		signature: signature,
+	}
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(nodeman.NewDist(client)), nodeman.WithReleaseKeyring(keyringPath))
+
+	_, err = manager.GetNode("18.14.0")
+
+	assert.ErrorContains(t, err, "No checksum published")
+	assert.Empty(t, archivesIn(t, home))
+}
+
+// releaseFixture reads the checksums of a node release and their signature by a node releaser,
+// which task release-keys downloads along with the keys
+func releaseFixture(t *testing.T) (string, []byte) {
+	shasums, err := os.ReadFile(filepath.Join("testdata", "SHASUMS256.txt"))
+	if os.IsNotExist(err) {
+		t.Skip("run task release-keys to add the node release fixture")
+	}
+	assert.Nil(t, err)
+	signature, err := os.ReadFile(filepath.Join("testdata", "SHASUMS256.txt.asc"))
+	assert.Nil(t, err)
+	return string(shasums), signature
+}
+
+func TestGetNodeVerifiesReleaseWithBuiltInKeys(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	shasums, signature := releaseFixture(t)
+	client := &releaseClientMock{shasums: func(string) string { return shasums }, signature: signature}
+	keyringPath := filepath.Join(home, "missing.asc")
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(nodeman.NewDist(client)), nodeman.WithReleaseKeyring(keyringPath))
+
+	_, err := manager.GetNode("18.14.0")
+
+	// the signature checks out, so verification moves on to the archive, which the release does not list
+	assert.ErrorContains(t, err, "No checksum published")
+	assert.Empty(t, archivesIn(t, home))
+}
+
+func TestGetNodeRejectsTamperedReleaseWithBuiltInKeys(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	shasums, signature := releaseFixture(t)
+	tampered := strings.Replace(shasums, shasums[:8], "00000000", 1)
+	client := &releaseClientMock{shasums: func(string) string { return tampered }, signature: signature}
+	keyringPath := filepath.Join(home, "missing.asc")
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(nodeman.NewDist(client)), nodeman.WithReleaseKeyring(keyringPath))
+
+	_, err := manager.GetNode("18.14.0")
+
+	assert.ErrorContains(t, err, "Invalid signature")
+	assert.Empty(t, archivesIn(t, home))
+}
@@ -0,0 +1,118 @@
+package nodeman
+
+import (
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+// DefaultDistURL the official node.js distribution
+const DefaultDistURL = "https://nodejs.org/dist"
+
+// DefaultScheduleURL the node.js release schedule, listing the LTS phases of every major version
+const DefaultScheduleURL = "https://raw.githubusercontent.com/nodejs/Release/main/schedule.json"
+
+// DefaultUnofficialDistURL the node.js unofficial-builds distribution, used for musl based linux
+const DefaultUnofficialDistURL = "https://unofficial-builds.nodejs.org/download/release"
+
+// Dist is a node.js distribution site, either nodejs.org or a mirror of it,
+// serving index.json and the release archives
+// Use NewDist to create an instance of this object
+type Dist struct {
+	BaseURL           string
+	UnofficialBaseURL string
+	ScheduleURL       string
+	Username          string
+	Password          string
+	Token             string
+	// CacheDir where index.json is cached, it is not cached on disk when empty
+	CacheDir string
+	CacheTTL time.Duration
+	// Offline resolves versions from the cache and LocalVersions without any requests
+	Offline       bool
+	LocalVersions []string
+	client        HTTPClient
+	platform      platform
+	releases      *[]nodeLTSSchedule
+	schedule      map[string]nodeReleaseLine
+}
+
+// NewDist creates a distribution that defaults to nodejs.org, or unofficial-builds on musl based linux.
+// Once a mirror is set, musl builds come from the mirror too unless an unofficial mirror is set.
+func NewDist(client HTTPClient, options ...func(*Dist)) *Dist {
+	dist := &Dist{
+		BaseURL:     DefaultDistURL,
+		ScheduleURL: DefaultScheduleURL,
+		CacheTTL:    DefaultIndexTTL,
+		client:      client,
+		platform:    detectPlatform(),
+	}
+	for _, option := range options {
+		option(dist)
+	}
+	if dist.BaseURL == "" {
+		dist.BaseURL = DefaultDistURL
+	}
+	dist.BaseURL = strings.TrimSuffix(dist.BaseURL, "/")
+	if dist.UnofficialBaseURL == "" {
+		dist.UnofficialBaseURL = DefaultUnofficialDistURL
+		if dist.BaseURL != DefaultDistURL {
+			dist.UnofficialBaseURL = dist.BaseURL
+		}
+	}
+	if dist.ScheduleURL == "" {
+		dist.ScheduleURL = DefaultScheduleURL
+	}
+	dist.UnofficialBaseURL = strings.TrimSuffix(dist.UnofficialBaseURL, "/")
+	return dist
+}
+
+// Do sends a request to the distribution, adding credentials for authenticated mirrors
+// to the requests that go to the mirror's host
+func (d *Dist) Do(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	isMirror := strings.HasPrefix(url, d.BaseURL+"/") ||
+		(strings.HasPrefix(url, d.UnofficialBaseURL+"/") && sameHost(d.UnofficialBaseURL, d.BaseURL))
+	switch {
+	case !isMirror:
+	case d.Token != "":
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", d.Token))
+	case d.Username != "" || d.Password != "":
+		req.SetBasicAuth(d.Username, d.Password)
+	}
+	return d.client.Do(req)
+}
+
+// sameHost reports whether both URLs point at the same scheme, host and port
+func sameHost(a string, b string) bool {
+	urlA, errA := neturl.Parse(a)
+	urlB, errB := neturl.Parse(b)
+	return errA == nil && errB == nil && urlA.Scheme == urlB.Scheme && urlA.Host == urlB.Host
+}
+
+// isUnofficial reports whether releases for this platform come from unofficial-builds,
+// which do not publish signed checksums
+func (d *Dist) isUnofficial() bool {
+	return d.platform.musl
+}
+
+func (d *Dist) baseURL() string {
+	if d.isUnofficial() {
+		return d.UnofficialBaseURL
+	}
+	return d.BaseURL
+}
+
+func (d *Dist) indexURL() string {
+	return fmt.Sprintf("%s/index.json", d.baseURL())
+}
+
+func (d *Dist) releaseURL(version string) string {
+	return fmt.Sprintf("%s/v%s", d.baseURL(), version)
+}
+
+func (d *Dist) archiveURL(version string) string {
+	return fmt.Sprintf("%s/%s", d.releaseURL(version), d.platform.archiveName(version))
+}
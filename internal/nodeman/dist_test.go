@@ -0,0 +1,69 @@
+package nodeman_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMirrorServer(t *testing.T, authorized func(r *http.Request) bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/artifactory/node/index.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(defaultBody)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDistMirrorBasicAuth(t *testing.T) {
+	server := newMirrorServer(t, func(r *http.Request) bool {
+		username, password, ok := r.BasicAuth()
+		return ok && username == "builder" && password == "secret"
+	})
+	dist := nodeman.NewDist(http.DefaultClient, func(d *nodeman.Dist) {
+		d.BaseURL = server.URL + "/artifactory/node/"
+		d.Username = "builder"
+		d.Password = "secret"
+	})
+
+	version, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "12.x", dist)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "12.19.0", version)
+}
+
+func TestDistMirrorBearerAuth(t *testing.T) {
+	server := newMirrorServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer my-token"
+	})
+	dist := nodeman.NewDist(http.DefaultClient, func(d *nodeman.Dist) {
+		d.BaseURL = server.URL + "/artifactory/node"
+		d.Token = "my-token"
+	})
+
+	version, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "10.x", dist)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "10.23.0", version)
+}
+
+func TestDistMirrorUnauthorized(t *testing.T) {
+	server := newMirrorServer(t, func(r *http.Request) bool { return false })
+	dist := nodeman.NewDist(http.DefaultClient, func(d *nodeman.Dist) {
+		d.BaseURL = server.URL + "/artifactory/node"
+	})
+
+	_, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "", dist)
+
+	assert.ErrorContains(t, err, "401")
+}
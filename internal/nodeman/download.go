@@ -0,0 +1,144 @@
+package nodeman
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mholt/archiver"
+	"github.com/rdaniels6813/cli-manager/internal/progress"
+)
+
+// partialSuffix marks an archive that is still being downloaded, it is renamed once complete
+const partialSuffix = ".part"
+
+// extractPrefix marks a folder a node archive is being extracted into
+const extractPrefix = ".extract-"
+
+// downloadNodeArchive downloads the node archive for the version, resuming a previously interrupted download
+// The archive is written to a partial file that is only renamed to the archive path once it is complete.
+func (m *Manager) downloadNodeArchive(version string) (string, error) {
+	nodeBaseFolder := m.getNodeBaseFolder()
+	url := m.getNodeURL(version)
+	archiveName := filepath.Base(url)
+	nodeBinaryPath := filepath.Join(nodeBaseFolder, archiveName)
+	if _, err := m.os.Stat(nodeBinaryPath); err == nil {
+		return nodeBinaryPath, nil
+	}
+	partialPath := nodeBinaryPath + partialSuffix
+	out, err := os.OpenFile(partialPath, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create destination file for node binary: %w", err)
+	}
+	defer out.Close()
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read partial node binary: %w", err)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := m.dist.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Failed download node binary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			out.Close()
+			os.Remove(partialPath)
+			return "", fmt.Errorf("Failed download node binary: unexpected range %s", resp.Header.Get("Content-Range"))
+		}
+	case http.StatusOK:
+		// The server ignored the range, start over
+		offset = 0
+		err = out.Truncate(0)
+		if err != nil {
+			return "", fmt.Errorf("Failed write node binary: %w", err)
+		}
+		_, err = out.Seek(0, io.SeekStart)
+		if err != nil {
+			return "", fmt.Errorf("Failed write node binary: %w", err)
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file already holds the whole archive, the checksum decides if it is usable
+		out.Close()
+		return nodeBinaryPath, os.Rename(partialPath, nodeBinaryPath)
+	default:
+		return "", fmt.Errorf("Failed download node binary: %s", resp.Status)
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	reporter, finish := m.startProgress(archiveName, total, offset)
+	_, err = io.Copy(io.MultiWriter(out, reporter), resp.Body)
+	finish()
+	if err != nil {
+		return "", fmt.Errorf("Failed write node binary, run the command again to resume: %w", err)
+	}
+	err = out.Close()
+	if err != nil {
+		return "", fmt.Errorf("Failed write node binary: %w", err)
+	}
+	err = os.Rename(partialPath, nodeBinaryPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to move node binary into place: %w", err)
+	}
+	return nodeBinaryPath, nil
+}
+
+func (m *Manager) startProgress(name string, total int64, offset int64) (io.Writer, func()) {
+	switch {
+	case m.progress == nil:
+		return io.Discard, func() {}
+	case m.interactive:
+		bar := progress.NewBar(m.progress, name, total, offset)
+		return bar, bar.Finish
+	default:
+		if offset > 0 {
+			fmt.Fprintf(m.progress, "Resuming download of %s at %s\n", name, progress.FormatBytes(offset))
+		} else {
+			fmt.Fprintf(m.progress, "Downloading %s\n", name)
+		}
+		return io.Discard, func() {}
+	}
+}
+
+// unpackNodeArchive extracts the archive into a temporary folder and moves it into place,
+// so an interrupted extraction never leaves a partial node installation behind
+func (m *Manager) unpackNodeArchive(path string, version string) error {
+	extractFolder, err := os.MkdirTemp(m.getNodeBaseFolder(), extractPrefix+version+"-")
+	if err != nil {
+		return fmt.Errorf("Failed to get temp dir: %w", err)
+	}
+	defer os.RemoveAll(extractFolder)
+	err = archiver.Unarchive(path, extractFolder)
+	if err != nil {
+		return fmt.Errorf("Failed to unarchive: %w", err)
+	}
+	dirs, err := os.ReadDir(extractFolder)
+	if err != nil {
+		return fmt.Errorf("Failed to read dir: %w", err)
+	}
+	if len(dirs) != 1 || !dirs[0].IsDir() {
+		return fmt.Errorf("Unexpected contents in node archive: %s", filepath.Base(path))
+	}
+	err = os.Rename(filepath.Join(extractFolder, dirs[0].Name()), m.getNodeOutputFolder(version))
+	if err != nil {
+		return fmt.Errorf("Failed to rename archive paths: %w", err)
+	}
+	os.Remove(path)
+	return nil
+}
@@ -0,0 +1,117 @@
+package nodeman_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mholt/archiver"
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func archiveExtension() string {
+	switch runtime.GOOS {
+	case "windows":
+		return ".zip"
+	case "darwin":
+		return ".tar.gz"
+	}
+	return ".tar.xz"
+}
+
+func newNodeArchive(t *testing.T) []byte {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "node-v18.14.0")
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "bin"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "bin", "node"), []byte("#!/bin/sh\n"), 0755))
+	archivePath := filepath.Join(dir, "node"+archiveExtension())
+	assert.Nil(t, archiver.Archive([]string{root}, archivePath))
+	data, err := os.ReadFile(archivePath)
+	assert.Nil(t, err)
+	return data
+}
+
+type distServer struct {
+	*httptest.Server
+	archive     []byte
+	archiveName string
+	ranges      []string
+	interruptAt int
+}
+
+func newDistServer(t *testing.T, archive []byte) *distServer {
+	server := &distServer{archive: archive}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/SHASUMS256.txt") {
+			fmt.Fprintf(w, "%s  %s\n", checksumOf(server.archive), server.archiveName)
+			return
+		}
+		server.archiveName = path.Base(r.URL.Path)
+		server.ranges = append(server.ranges, r.Header.Get("Range"))
+		if server.interruptAt > 0 {
+			w.Header().Set("Content-Length", fmt.Sprint(len(server.archive)))
+			_, _ = w.Write(server.archive[:server.interruptAt])
+			w.(http.Flusher).Flush()
+			server.interruptAt = 0
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, server.archiveName, time.Time{}, bytes.NewReader(server.archive))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *distServer) dist() *nodeman.Dist {
+	return nodeman.NewDist(http.DefaultClient, func(d *nodeman.Dist) {
+		d.BaseURL = s.URL
+	})
+}
+
+func TestGetNodeDownloadsAndUnpacks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	server := newDistServer(t, newNodeArchive(t))
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(server.dist()))
+
+	node, err := manager.GetNode("18.14.0")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{""}, server.ranges)
+	if runtime.GOOS != "windows" {
+		_, err = os.Stat(filepath.Join(node.BinPath(), "node"))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, []string{"18.14.0"}, archivesIn(t, home))
+}
+
+func TestGetNodeResumesInterruptedDownload(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	archive := newNodeArchive(t)
+	server := newDistServer(t, archive)
+	half := len(archive) / 2
+	server.interruptAt = half
+	var out bytes.Buffer
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithDist(server.dist()), nodeman.WithProgress(&out, false))
+
+	_, err := manager.GetNode("18.14.0")
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{server.archiveName + ".part"}, archivesIn(t, home))
+
+	_, err = manager.GetNode("18.14.0")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", fmt.Sprintf("bytes=%d-", half)}, server.ranges)
+	assert.Contains(t, out.String(), "Resuming download")
+	assert.Equal(t, []string{"18.14.0"}, archivesIn(t, home))
+}
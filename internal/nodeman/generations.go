@@ -0,0 +1,107 @@
+package nodeman
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// GetAppHistory returns the installed version of the app followed by the previous versions kept on disk
+func (m *Manager) GetAppHistory(appName string) ([]*CLIApp, error) {
+	app, err := m.GetCLIApp(appName)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*CLIApp{app}, app.Previous...), nil
+}
+
+// Rollback switches the app back to a previous version kept on disk, the newest one unless a version is given
+func (m *Manager) Rollback(appName string, version string) (*CLIApp, error) {
+	current, err := m.GetCLIApp(appName)
+	if err != nil {
+		return nil, err
+	}
+	if len(current.Previous) == 0 {
+		return nil, fmt.Errorf("There are no previous versions of %s to roll back to", current.App)
+	}
+	index := 0
+	if version != "" {
+		index = -1
+		for i, generation := range current.Previous {
+			if generation.Version == strings.TrimPrefix(version, "v") {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return nil, fmt.Errorf("%s %s is not kept on disk, see cli-manager history %s", current.App, version, current.App)
+		}
+	}
+	target := *current.Previous[index]
+	for _, path := range []string{target.Prefix, target.Path} {
+		if _, err := m.os.Stat(path); os.IsNotExist(err) {
+			return nil, fmt.Errorf("Cannot roll back %s to %s, %s no longer exists", current.App, target.Version, path)
+		}
+	}
+	apps := m.loadApps()
+	for _, bin := range target.Bins {
+		if existing, ok := findAppByBin(apps, bin); ok && existing.App != current.App {
+			return nil, fmt.Errorf("Cannot roll back %s, its %s command is now provided by %s", current.App, bin, existing.App)
+		}
+	}
+	snapshot := *current
+	snapshot.Previous = nil
+	target.Previous = append([]*CLIApp{&snapshot}, current.Previous[:index]...)
+	target.Previous = append(target.Previous, current.Previous[index+1:]...)
+	target.InstallName = current.InstallName
+	target.NodePolicy = current.NodePolicy
+	target.NodeRange = current.NodeRange
+	target.UpdatedAt = time.Now().UTC()
+	apps[current.App] = &target
+	return &target, m.saveApps(apps)
+}
+
+// UsesPrefix reports whether the app or one of its previous versions is installed into prefix
+func (a *CLIApp) UsesPrefix(prefix string) bool {
+	if a.Prefix == prefix {
+		return true
+	}
+	for _, generation := range a.Previous {
+		if generation.Prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// keepGenerations returns the versions to keep after replacing the previous record with an install
+// into prefix, removing the versions beyond the number of generations from disk
+func (m *Manager) keepGenerations(previous *CLIApp, prefix string) []*CLIApp {
+	if previous == nil {
+		return nil
+	}
+	candidates := previous.Previous
+	if previous.Prefix != "" && previous.Prefix != prefix {
+		snapshot := *previous
+		snapshot.Previous = nil
+		candidates = append([]*CLIApp{&snapshot}, candidates...)
+	}
+	kept := []*CLIApp{}
+	for _, generation := range candidates {
+		switch {
+		case generation.Prefix == prefix:
+		case len(kept) < m.generations:
+			kept = append(kept, generation)
+		default:
+			err := m.RemoveAppPrefix(generation.Prefix)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to remove %s %s: %s\n", generation.App, generation.Version, err)
+			}
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
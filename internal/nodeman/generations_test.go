@@ -0,0 +1,88 @@
+package nodeman_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func installVersions(t *testing.T, manager *nodeman.Manager, nodePath string, versions ...string) []string {
+	prefixes := []string{}
+	for _, version := range versions {
+		pkg := &nodeman.NpmViewResponse{Name: "typescript", Version: version}
+		prefix, err := manager.InstallApp(&npmNode{t: t}, "typescript@"+version, pkg)
+		assert.Nil(t, err)
+		err = manager.MarkInstalled(&nodeman.CLIApp{
+			App: "typescript", InstallName: "typescript", Version: version, Path: nodePath, Prefix: prefix,
+		}, map[string]string{"tsc": "bin/tsc"})
+		assert.Nil(t, err)
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+func versionsOf(apps []*nodeman.CLIApp) []string {
+	versions := []string{}
+	for _, app := range apps {
+		versions = append(versions, app.Version)
+	}
+	return versions
+}
+
+func TestGenerationsAreKept(t *testing.T) {
+	nodeFolder := setupNodeRuntimes(t)
+	nodePath := filepath.Join(nodeFolder, "16.19.1", "bin")
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithGenerations(1))
+	assert.Nil(t, manager.MarkUninstalled("typescript"))
+
+	prefixes := installVersions(t, manager, nodePath, "4.9.4", "4.9.5", "5.0.2")
+
+	history, err := manager.GetAppHistory("typescript")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"5.0.2", "4.9.5"}, versionsOf(history))
+	assert.NoDirExists(t, prefixes[0])
+	assert.DirExists(t, prefixes[1])
+	_, err = manager.RemoveNode("16.19.1")
+	assert.ErrorContains(t, err, "used by: typescript")
+}
+
+func TestRollback(t *testing.T) {
+	nodeFolder := setupNodeRuntimes(t)
+	nodePath := filepath.Join(nodeFolder, "16.19.1", "bin")
+	manager := nodeman.NewManager(afero.NewOsFs())
+	assert.Nil(t, manager.MarkUninstalled("typescript"))
+	prefixes := installVersions(t, manager, nodePath, "4.9.4", "4.9.5", "5.0.2")
+
+	app, err := manager.Rollback("typescript", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "4.9.5", app.Version)
+	path, err := manager.GetCommandPath("tsc")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(prefixes[1], "bin", "tsc"), path)
+
+	app, err = manager.Rollback("typescript", "v4.9.4")
+	assert.Nil(t, err)
+	assert.Equal(t, "4.9.4", app.Version)
+	history, err := manager.GetAppHistory("typescript")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"4.9.4", "4.9.5", "5.0.2"}, versionsOf(history))
+
+	_, err = manager.Rollback("typescript", "3.9.0")
+	assert.ErrorContains(t, err, "typescript 3.9.0 is not kept on disk")
+	assert.Nil(t, os.RemoveAll(prefixes[1]))
+	_, err = manager.Rollback("typescript", "")
+	assert.ErrorContains(t, err, "no longer exists")
+}
+
+func TestRollbackWithoutPreviousVersions(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	_, err := manager.Rollback("typescript", "")
+
+	assert.ErrorContains(t, err, "There are no previous versions of typescript")
+}
@@ -0,0 +1,67 @@
+package nodeman
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rdaniels6813/cli-manager/internal/gitsource"
+	"github.com/rdaniels6813/cli-manager/internal/store"
+	"github.com/rdaniels6813/cli-manager/internal/token"
+)
+
+// githubToken returns the GitHub token used to read private repositories
+func githubToken() (string, error) {
+	return token.NewConfigTokenManager(store.GetDefaultStore()).GetNewOrSavedToken([]string{})
+}
+
+// viewGitPackage reads the package.json of a git source at the commit its ref resolves to
+func (m *Manager) viewGitPackage(source *gitsource.Source) (*NpmViewResponse, error) {
+	resolved, err := m.git.Resolve(source)
+	if err != nil {
+		return nil, err
+	}
+	data, err := m.git.ReadFile(source, resolved, "package.json")
+	if err != nil {
+		return nil, err
+	}
+	var pkg NpmViewResponse
+	err = json.Unmarshal(data, &pkg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the package.json of %s: %w", source.URL, err)
+	}
+	if pkg.Name == "" {
+		return nil, fmt.Errorf("The package.json of %s has no name", source.URL)
+	}
+	err = validatePackage(&pkg)
+	if err != nil {
+		return nil, fmt.Errorf("The package.json of %s is invalid: %w", source.URL, err)
+	}
+	pkg.Commit = resolved.Commit
+	pkg.Dist = PackageDist{Tarball: source.Pinned(resolved.Commit)}
+	return &pkg, nil
+}
+
+// gitStatus compares the installed commit of an app with the one its ref resolves to now
+func (m *Manager) gitStatus(app *CLIApp, source *gitsource.Source, status *AppStatus) *AppStatus {
+	status.Installed = shortCommit(app.Commit)
+	resolved, err := m.git.Resolve(source)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Wanted = shortCommit(resolved.Commit)
+	if resolved.Tag != "" {
+		status.Latest = resolved.Tag
+	}
+	// specs pinned to an abbreviated commit resolve to it as given, while the full commit is recorded at install
+	status.Outdated = !strings.HasPrefix(strings.ToLower(app.Commit), resolved.Commit)
+	return status
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
@@ -0,0 +1,108 @@
+package nodeman_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+// gitCommit commits a package.json with the version into the repository at dir, returning the commit
+func gitCommit(t *testing.T, dir string, version string) string {
+	return gitCommitPackage(t, dir, "corp-cli", version)
+}
+
+func gitCommitPackage(t *testing.T, dir string, name string, version string) string {
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		assert.Nil(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		git("init", "-q", "-b", "main")
+	}
+	pkg := `{"name": "` + name + `", "version": "` + version + `", "bin": {"corp": "bin/corp.js"}, "engines": {"node": ">=16"}}`
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "cli"), 0700))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "cli", "package.json"), []byte(pkg), 0600))
+	git("add", "-A")
+	git("commit", "-q", "-m", version)
+	return git("rev-parse", "HEAD")
+}
+
+func TestGitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := t.TempDir()
+	first := gitCommit(t, repo, "1.0.0")
+	spec := "git+file://" + filepath.ToSlash(repo) + "#main::path:cli"
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	pkg, err := manager.ViewPackage(&nodeman.CLIApp{}, spec)
+	assert.Nil(t, err)
+	assert.Equal(t, "corp-cli", pkg.Name)
+	assert.Equal(t, first, pkg.Commit)
+	assert.Equal(t, ">=16", pkg.Engines["node"])
+
+	prefix, err := manager.InstallApp(&npmNode{t: t}, spec, pkg)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(home, ".cli-manager", "apps", "corp-cli", "1.0.0-"+first[:7]), prefix)
+	installed, err := os.ReadFile(filepath.Join(prefix, "installed"))
+	assert.Nil(t, err)
+	assert.Equal(t, "git+file://"+filepath.ToSlash(repo)+"#"+first+"::path:cli", string(installed))
+
+	app := &nodeman.CLIApp{App: "corp-cli", InstallName: spec, Version: "1.0.0", Commit: first}
+	status := manager.GetAppStatus(app)
+	assert.False(t, status.Outdated)
+	assert.Equal(t, first[:7], status.Installed)
+
+	second := gitCommit(t, repo, "1.0.1")
+	status = manager.GetAppStatus(app)
+	assert.True(t, status.Outdated)
+	assert.Equal(t, second[:7], status.Wanted)
+	assert.Empty(t, status.Error)
+}
+
+func TestInvalidGitPackage(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := t.TempDir()
+	gitCommitPackage(t, repo, "../../../.ssh", "1.0.0")
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	_, err := manager.ViewPackage(&nodeman.CLIApp{}, "git+file://"+filepath.ToSlash(repo)+"#main::path:cli")
+
+	assert.ErrorContains(t, err, "not a valid npm package name")
+}
+
+func TestGitSourcePinnedToShortCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	first := gitCommit(t, repo, "1.0.0")
+	gitCommit(t, repo, "1.0.1")
+	spec := "git+file://" + filepath.ToSlash(repo) + "#" + first[:7] + "::path:cli"
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	pkg, err := manager.ViewPackage(&nodeman.CLIApp{}, spec)
+	assert.Nil(t, err)
+	assert.Equal(t, first, pkg.Commit)
+
+	status := manager.GetAppStatus(&nodeman.CLIApp{App: "corp-cli", InstallName: spec, Version: "1.0.0", Commit: pkg.Commit})
+	assert.False(t, status.Outdated)
+	assert.Empty(t, status.Error)
+}
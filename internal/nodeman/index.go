@@ -0,0 +1,175 @@
+package nodeman
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rdaniels6813/cli-manager/internal/util"
+	"github.com/spf13/afero"
+)
+
+// DefaultIndexTTL how long a cached copy of index.json is used before it is revalidated
+const DefaultIndexTTL = time.Hour
+
+const indexCacheFile = "index.json"
+
+// errNotCached returned when offline and there is no cached copy of a file
+var errNotCached = errors.New("not available offline")
+
+// cacheMeta describes a cached copy of a file from the distribution
+type cacheMeta struct {
+	URL       string    `json:"url"`
+	ETag      string    `json:"etag"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// getNodeReleases returns the releases listed in index.json, fetched at most once per Dist
+func getNodeReleases(dist *Dist) (*[]nodeLTSSchedule, error) {
+	if dist.releases == nil {
+		releases, err := dist.loadReleases()
+		if err != nil {
+			return nil, err
+		}
+		dist.releases = &releases
+	}
+	return dist.releases, nil
+}
+
+func (d *Dist) loadReleases() ([]nodeLTSSchedule, error) {
+	body, err := d.fetchCached(d.indexURL(), indexCacheFile)
+	if err != nil && !errors.Is(err, errNotCached) {
+		return nil, err
+	}
+	var releases []nodeLTSSchedule
+	if body != nil {
+		err = json.Unmarshal(body, &releases)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if d.Offline {
+		return d.offlineReleases(releases)
+	}
+	return releases, nil
+}
+
+// fetchCached returns the file at url, using the copy cached under name while it is within the TTL
+// and revalidating it with its ETag afterwards. When offline only the cached copy is used.
+func (d *Dist) fetchCached(url string, name string) ([]byte, error) {
+	cached, meta := d.readCache(url, name)
+	if d.Offline {
+		if cached == nil {
+			return nil, fmt.Errorf("%s is %w", url, errNotCached)
+		}
+		return cached, nil
+	}
+	if cached != nil && time.Since(meta.FetchedAt) < d.CacheTTL {
+		return cached, nil
+	}
+	body, etag, err := d.fetchWithETag(url, meta.ETag)
+	if err != nil {
+		if cached != nil {
+			fmt.Fprintf(os.Stderr, "Failed to refresh %s, using cached copy: %s\n", url, err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	d.writeCache(name, body, cacheMeta{URL: url, ETag: etag, FetchedAt: time.Now()})
+	if body == nil {
+		return cached, nil
+	}
+	return body, nil
+}
+
+// fetchWithETag downloads the file, returning a nil body when the copy matching etag is still current
+func (d *Dist) fetchWithETag(url string, etag string) ([]byte, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := d.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, etag, nil
+	case http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		return body, resp.Header.Get("ETag"), err
+	}
+	return nil, "", fmt.Errorf("Failed to get %s: %s", url, resp.Status)
+}
+
+// readCache returns the cached copy of the file at url, or nil if there is none
+func (d *Dist) readCache(url string, name string) ([]byte, cacheMeta) {
+	var meta cacheMeta
+	if d.CacheDir == "" {
+		return nil, meta
+	}
+	data, err := os.ReadFile(filepath.Join(d.CacheDir, name+".meta"))
+	if err != nil || json.Unmarshal(data, &meta) != nil || meta.URL != url {
+		return nil, cacheMeta{}
+	}
+	data, err = os.ReadFile(filepath.Join(d.CacheDir, name))
+	if err != nil {
+		return nil, cacheMeta{}
+	}
+	return data, meta
+}
+
+// writeCache stores a file, a nil body only refreshes the metadata of the current copy
+func (d *Dist) writeCache(name string, body []byte, meta cacheMeta) {
+	if d.CacheDir == "" {
+		return
+	}
+	err := os.MkdirAll(d.CacheDir, 0700)
+	if err == nil && body != nil {
+		err = util.WriteFileAtomic(afero.NewOsFs(), filepath.Join(d.CacheDir, name), body, 0600)
+	}
+	if err == nil {
+		var data []byte
+		data, err = json.Marshal(meta)
+		if err == nil {
+			err = util.WriteFileAtomic(afero.NewOsFs(), filepath.Join(d.CacheDir, name+".meta"), data, 0600)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to cache %s: %s\n", meta.URL, err)
+	}
+}
+
+// offlineReleases limits the releases to the node versions that are already downloaded,
+// using the cached index for their metadata when it is available
+func (d *Dist) offlineReleases(cached []nodeLTSSchedule) ([]nodeLTSSchedule, error) {
+	local := make(map[string]bool, len(d.LocalVersions))
+	for _, version := range d.LocalVersions {
+		local[strings.TrimPrefix(version, "v")] = true
+	}
+	result := []nodeLTSSchedule{}
+	for _, release := range cached {
+		version := strings.TrimPrefix(release.Version, "v")
+		if local[version] {
+			result = append(result, release)
+			delete(local, version)
+		}
+	}
+	for version := range local {
+		result = append(result, nodeLTSSchedule{Version: "v" + version, LTS: false})
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("No node versions are available offline, install one while online first")
+	}
+	return result, nil
+}
@@ -0,0 +1,137 @@
+package nodeman_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/stretchr/testify/assert"
+)
+
+type indexServer struct {
+	*httptest.Server
+	requests     int
+	revalidation int
+	down         bool
+}
+
+func newIndexServer(t *testing.T) *indexServer {
+	server := &indexServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests++
+		if server.down {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			server.revalidation++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(defaultBody)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *indexServer) dist(cacheDir string, options ...func(*nodeman.Dist)) *nodeman.Dist {
+	return nodeman.NewDist(http.DefaultClient, append([]func(*nodeman.Dist){func(d *nodeman.Dist) {
+		d.BaseURL = s.URL
+		d.UnofficialBaseURL = s.URL
+		d.CacheDir = cacheDir
+	}}, options...)...)
+}
+
+func TestIndexFetchedOncePerDist(t *testing.T) {
+	server := newIndexServer(t)
+	dist := server.dist("")
+
+	_, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "", dist)
+	assert.Nil(t, err)
+	_, err = nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "12.x", dist)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, server.requests)
+}
+
+func TestIndexCachedWithinTTL(t *testing.T) {
+	server := newIndexServer(t)
+	cacheDir := t.TempDir()
+
+	_, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "", server.dist(cacheDir))
+	assert.Nil(t, err)
+	version, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "12.x", server.dist(cacheDir))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "12.19.0", version)
+	assert.Equal(t, 1, server.requests)
+}
+
+func TestIndexRevalidatedWithETagAfterTTL(t *testing.T) {
+	server := newIndexServer(t)
+	cacheDir := t.TempDir()
+	expired := func(d *nodeman.Dist) { d.CacheTTL = 0 }
+
+	_, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "", server.dist(cacheDir, expired))
+	assert.Nil(t, err)
+	version, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "10.x", server.dist(cacheDir, expired))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "10.23.0", version)
+	assert.Equal(t, 2, server.requests)
+	assert.Equal(t, 1, server.revalidation)
+}
+
+func TestIndexFallsBackToStaleCache(t *testing.T) {
+	server := newIndexServer(t)
+	cacheDir := t.TempDir()
+	expired := func(d *nodeman.Dist) { d.CacheTTL = 0 }
+	_, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "", server.dist(cacheDir, expired))
+	assert.Nil(t, err)
+	server.down = true
+
+	version, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "12.x", server.dist(cacheDir, expired))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "12.19.0", version)
+}
+
+func TestIndexOfflineUsesDownloadedVersions(t *testing.T) {
+	server := newIndexServer(t)
+	cacheDir := t.TempDir()
+	_, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "", server.dist(cacheDir))
+	assert.Nil(t, err)
+	offline := func(d *nodeman.Dist) {
+		d.Offline = true
+		d.LocalVersions = []string{"12.18.0", "10.23.0"}
+	}
+
+	version, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, ">=10.x", server.dist(cacheDir, offline))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "12.18.0", version)
+	assert.Equal(t, 1, server.requests)
+}
+
+func TestIndexOfflineWithoutCache(t *testing.T) {
+	server := newIndexServer(t)
+	offline := func(d *nodeman.Dist) {
+		d.Offline = true
+		d.LocalVersions = []string{"16.19.1", "17.9.1"}
+	}
+
+	for _, policy := range []nodeman.Policy{nodeman.PolicyLTS, nodeman.PolicyActiveLTS, nodeman.PolicyMaintenance} {
+		version, err := nodeman.ResolveNodeVersion(policy, "^16", server.dist(t.TempDir(), offline))
+		assert.Nil(t, err, policy)
+		assert.Equal(t, "16.19.1", version, policy)
+	}
+	version, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "", server.dist(t.TempDir(), offline))
+	assert.Nil(t, err)
+	assert.Equal(t, "17.9.1", version)
+
+	_, err = nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "", server.dist(t.TempDir(), func(d *nodeman.Dist) { d.Offline = true }))
+	assert.ErrorContains(t, err, "No node versions are available offline")
+	assert.Equal(t, 0, server.requests)
+}
@@ -0,0 +1,135 @@
+package nodeman
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rdaniels6813/cli-manager/internal/util"
+)
+
+// installedSchema the current version of the installed.json format
+const installedSchema = 2
+
+// installedApps the contents of installed.json, keyed by app name
+type installedApps struct {
+	Schema int                `json:"schema"`
+	Apps   map[string]*CLIApp `json:"apps"`
+}
+
+// installedAppV1 an entry of the original installed.json format, which was keyed by bin name
+type installedAppV1 struct {
+	App         string `json:"app"`
+	Bin         string `json:"bin"`
+	Path        string `json:"path"`
+	Prefix      string `json:"prefix,omitempty"`
+	InstallName string `json:"install_name"`
+	NodePolicy  string `json:"node_policy,omitempty"`
+	NodeRange   string `json:"node_range,omitempty"`
+}
+
+// loadApps reads installed.json, migrating files in an older format and keeping a backup of them
+func (m *Manager) loadApps() map[string]*CLIApp {
+	path := m.getConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return map[string]*CLIApp{}
+	}
+	var installed installedApps
+	err = json.Unmarshal(data, &installed)
+	if err != nil {
+		return map[string]*CLIApp{}
+	}
+	if installed.Schema >= installedSchema {
+		if installed.Apps == nil {
+			installed.Apps = map[string]*CLIApp{}
+		}
+		return installed.Apps
+	}
+	var v1 map[string]*installedAppV1
+	err = json.Unmarshal(data, &v1)
+	if err != nil {
+		return map[string]*CLIApp{}
+	}
+	apps := m.migrateV1(v1)
+	err = util.WriteFileAtomic(m.os, fmt.Sprintf("%s.v%d.bak", path, 1), data, 0600)
+	if err == nil {
+		err = m.saveApps(apps)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to migrate %s: %s\n", path, err)
+	}
+	return apps
+}
+
+// migrateV1 groups the bins of the original format by app
+func (m *Manager) migrateV1(v1 map[string]*installedAppV1) map[string]*CLIApp {
+	nodeBaseFolder := m.getNodeBaseFolder()
+	apps := map[string]*CLIApp{}
+	for bin, entry := range v1 {
+		if entry == nil {
+			continue
+		}
+		app, ok := apps[entry.App]
+		if !ok {
+			nodeVersion, _ := nodeVersionOf(nodeBaseFolder, entry.Path)
+			app = &CLIApp{
+				App:         entry.App,
+				InstallName: entry.InstallName,
+				NodeVersion: nodeVersion,
+				Path:        entry.Path,
+				Prefix:      entry.Prefix,
+				NodePolicy:  entry.NodePolicy,
+				NodeRange:   entry.NodeRange,
+			}
+			apps[entry.App] = app
+		}
+		app.Bins = append(app.Bins, bin)
+		sort.Strings(app.Bins)
+	}
+	return apps
+}
+
+// saveApps writes installed.json in the current format and brings the shims & aliases files in line with it
+func (m *Manager) saveApps(apps map[string]*CLIApp) error {
+	data, err := json.MarshalIndent(installedApps{Schema: installedSchema, Apps: apps}, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = util.WriteFileAtomic(m.os, m.getConfigPath(), data, 0600)
+	if err != nil {
+		return err
+	}
+	err = m.writeShims(apps)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update the shims in %s: %s\n", GetShimFolder(m.os), err)
+	}
+	err = m.writeAliasesFiles(apps)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update the aliases files: %s\n", err)
+	}
+	return nil
+}
+
+// findAppByBin returns the app providing the bin
+func findAppByBin(apps map[string]*CLIApp, bin string) (*CLIApp, bool) {
+	for _, app := range apps {
+		for _, name := range app.Bins {
+			if name == bin {
+				return app, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// touch records when the app was installed and last updated
+func (a *CLIApp) touch(previous *CLIApp) {
+	a.UpdatedAt = time.Now().UTC()
+	a.InstalledAt = a.UpdatedAt
+	if previous != nil && !previous.InstalledAt.IsZero() {
+		a.InstalledAt = previous.InstalledAt
+	}
+}
@@ -0,0 +1,102 @@
+package nodeman_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+const installedV1 = `{
+	"ng": {"app": "@angular/cli", "bin": "ng", "path": "%[1]s/node/18.14.0/bin", "install_name": "@angular/cli"},
+	"tsc": {"app": "typescript", "bin": "tsc", "path": "%[1]s/node/16.19.1/bin", "install_name": "typescript@4",
+		"node_policy": "current"},
+	"tsserver": {"app": "typescript", "bin": "tsserver", "path": "%[1]s/node/16.19.1/bin", "install_name": "typescript@4",
+		"node_policy": "current"}
+}`
+
+func TestMigrateInstalledV1(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cliManagerDir := filepath.Join(home, ".cli-manager")
+	installedPath := filepath.Join(cliManagerDir, "installed.json")
+	v1 := []byte(fmt.Sprintf(installedV1, filepath.ToSlash(cliManagerDir)))
+	assert.Nil(t, os.MkdirAll(cliManagerDir, 0700))
+	assert.Nil(t, os.WriteFile(installedPath, v1, 0600))
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	apps := manager.GetCLIApps()
+
+	assert.Equal(t, []*nodeman.CLIApp{
+		{App: "@angular/cli", InstallName: "@angular/cli", NodeVersion: "18.14.0",
+			Path: filepath.Join(cliManagerDir, "node", "18.14.0", "bin"), Bins: []string{"ng"}},
+		{App: "typescript", InstallName: "typescript@4", NodeVersion: "16.19.1", NodePolicy: "current",
+			Path: filepath.Join(cliManagerDir, "node", "16.19.1", "bin"), Bins: []string{"tsc", "tsserver"}},
+	}, apps)
+	backup, err := os.ReadFile(installedPath + ".v1.bak")
+	assert.Nil(t, err)
+	assert.Equal(t, v1, backup)
+	var migrated struct {
+		Schema int                        `json:"schema"`
+		Apps   map[string]json.RawMessage `json:"apps"`
+	}
+	data, err := os.ReadFile(installedPath)
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(data, &migrated))
+	assert.Equal(t, 2, migrated.Schema)
+	assert.Len(t, migrated.Apps, 2)
+	path, err := manager.GetCommandPath("tsserver")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(cliManagerDir, "node", "16.19.1", "bin", "tsserver"), path)
+}
+
+func TestMarkInstalledKeepsInstallTime(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	first, err := manager.GetCLIApp("typescript")
+	assert.Nil(t, err)
+	assert.False(t, first.InstalledAt.IsZero())
+
+	err = manager.MarkInstalled(&nodeman.CLIApp{App: "typescript", Version: "5.0.2"}, map[string]string{"tsc": "bin/tsc"})
+	assert.Nil(t, err)
+
+	app, err := manager.GetCLIApp("typescript")
+	assert.Nil(t, err)
+	assert.Equal(t, "5.0.2", app.Version)
+	assert.Equal(t, []string{"tsc"}, app.Bins)
+	assert.True(t, first.InstalledAt.Equal(app.InstalledAt))
+	assert.False(t, app.UpdatedAt.Before(first.UpdatedAt))
+}
+
+func TestSetAliases(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	assert.Nil(t, manager.SetAliases("@angular/cli", map[string]string{"angular": "ng"}))
+	assert.Nil(t, manager.SetAliases("typescript", map[string]string{"ts": "tsc"}))
+
+	assert.Equal(t, map[string]string{"angular": "ng", "ts": "tsc"}, manager.GetAliases())
+	assert.ErrorContains(t, manager.SetAliases("eslint", nil), "not installed")
+}
+
+func TestSetAliasesRejectsUnsafeAliases(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	for alias, bin := range map[string]string{
+		"../../.bashrc": "tsc",
+		"..":            "tsc",
+		"ts; rm -rf ~":  "tsc",
+		"ts":            "ng",
+		"tsx":           "tsc'; touch pwned; '",
+	} {
+		err := manager.SetAliases("typescript", map[string]string{alias: bin})
+		assert.NotNil(t, err, alias)
+	}
+	assert.Empty(t, manager.GetAliases())
+}
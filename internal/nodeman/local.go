@@ -0,0 +1,165 @@
+package nodeman
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	filePrefix = "file:"
+	// linkPrefix marks folders that are symlinked into their prefix rather than copied, so edits take effect
+	linkPrefix = "link:"
+	// linkVersion names the prefix of linked apps, which follow the folder instead of a version
+	linkVersion = "link"
+)
+
+var tarballPattern = regexp.MustCompile(`(?i)\.(tgz|tar\.gz|tar)$`)
+
+// LocalSpec returns the spec of a local folder or tarball as file:<absolute path>, or link:<absolute path>
+// for linked folders, so it can be installed again from any folder. ok is false for specs that are not local.
+func LocalSpec(spec string) (string, bool) {
+	localPath, link, ok := parseLocalSpec(spec)
+	if !ok {
+		return "", false
+	}
+	if strings.HasPrefix(localPath, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			localPath = filepath.Join(home, localPath[2:])
+		}
+	}
+	absolute, err := filepath.Abs(localPath)
+	if err != nil {
+		return "", false
+	}
+	if link {
+		return linkPrefix + absolute, true
+	}
+	return filePrefix + absolute, true
+}
+
+// parseLocalSpec returns the path of a local folder or tarball spec, the way npm tells them from other specs
+func parseLocalSpec(spec string) (localPath string, link bool, ok bool) {
+	switch {
+	case strings.HasPrefix(spec, linkPrefix):
+		return strings.TrimPrefix(spec, linkPrefix), true, true
+	case strings.HasPrefix(spec, filePrefix):
+		return strings.TrimPrefix(spec, filePrefix), false, true
+	case strings.Contains(spec, "://"):
+		return "", false, false
+	case spec == "." || spec == ".." || filepath.IsAbs(spec) || tarballPattern.MatchString(spec):
+		return spec, false, true
+	}
+	for _, prefix := range []string{"./", "../", "/", "~/", `.\`, `..\`} {
+		if strings.HasPrefix(spec, prefix) {
+			return spec, false, true
+		}
+	}
+	return "", false, false
+}
+
+// viewLocalPackage reads the package.json of a local folder or tarball
+func viewLocalPackage(localPath string) (*NpmViewResponse, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	var integrity string
+	if info.IsDir() {
+		data, err = os.ReadFile(filepath.Join(localPath, "package.json"))
+	} else {
+		data, err = readTarballPackageJSON(localPath)
+		if err == nil {
+			integrity, err = fileIntegrity(localPath)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the package.json of %s: %w", localPath, err)
+	}
+	var pkg NpmViewResponse
+	err = json.Unmarshal(data, &pkg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the package.json of %s: %w", localPath, err)
+	}
+	if pkg.Name == "" {
+		return nil, fmt.Errorf("The package.json of %s has no name", localPath)
+	}
+	err = validatePackage(&pkg)
+	if err != nil {
+		return nil, fmt.Errorf("The package.json of %s is invalid: %w", localPath, err)
+	}
+	pkg.Dist = PackageDist{Tarball: filePrefix + localPath, Integrity: integrity}
+	return &pkg, nil
+}
+
+// readTarballPackageJSON returns the package.json at the top folder of a package tarball, gzipped or not
+func readTarballPackageJSON(tarball string) ([]byte, error) {
+	f, err := os.Open(tarball)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	buffered := bufio.NewReader(f)
+	var r io.Reader = buffered
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s has no package.json", tarball)
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimPrefix(path.Clean(filepath.ToSlash(header.Name)), "./")
+		if parts := strings.Split(name, "/"); len(parts) == 2 && parts[1] == "package.json" {
+			return io.ReadAll(archive)
+		}
+	}
+}
+
+// fileIntegrity returns the sha512 subresource integrity of a file, the form npm records for tarballs
+func fileIntegrity(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha512.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+	return "sha512-" + base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
+
+// localStatus compares the installed version of a local app with the one in its folder or tarball now,
+// linked apps are never outdated since they run from the folder
+func (m *Manager) localStatus(localPath string, link bool, status *AppStatus) *AppStatus {
+	pkg, err := viewLocalPackage(localPath)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Wanted = pkg.Version
+	status.Outdated = !link && status.Installed != pkg.Version
+	return status
+}
@@ -0,0 +1,136 @@
+package nodeman_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+const localPackageJSON = `{"name": "corp-cli", "version": "0.1.0", "bin": "bin/corp.js", "engines": {"node": ">=18"}}`
+
+func writePackage(t *testing.T, dir string, packageJSON string) {
+	assert.Nil(t, os.MkdirAll(dir, 0700))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(packageJSON), 0600))
+}
+
+func writeTarball(t *testing.T, file string) {
+	f, err := os.Create(file)
+	assert.Nil(t, err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	archive := tar.NewWriter(gz)
+	for name, content := range map[string]string{"package/README.md": "# corp-cli", "package/package.json": localPackageJSON} {
+		assert.Nil(t, archive.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}))
+		_, err = archive.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, archive.Close())
+	assert.Nil(t, gz.Close())
+}
+
+func TestLocalSpec(t *testing.T) {
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	for spec, expected := range map[string]string{
+		"./corp-cli":            "file:" + filepath.Join(cwd, "corp-cli"),
+		"..":                    "file:" + filepath.Dir(cwd),
+		"file:corp-cli":         "file:" + filepath.Join(cwd, "corp-cli"),
+		"corp-cli-0.1.0.tgz":    "file:" + filepath.Join(cwd, "corp-cli-0.1.0.tgz"),
+		"link:../corp-cli":      "link:" + filepath.Join(filepath.Dir(cwd), "corp-cli"),
+		filepath.Join(cwd, "x"): "file:" + filepath.Join(cwd, "x"),
+	} {
+		local, ok := nodeman.LocalSpec(spec)
+		assert.True(t, ok, spec)
+		assert.Equal(t, expected, local, spec)
+	}
+	for _, spec := range []string{"typescript", "@corp/cli@1", "owner/repo", "github:owner/repo", "https://example.com/cli.tgz"} {
+		_, ok := nodeman.LocalSpec(spec)
+		assert.False(t, ok, spec)
+	}
+}
+
+func TestViewLocalPackage(t *testing.T) {
+	dir := t.TempDir()
+	writePackage(t, filepath.Join(dir, "corp-cli"), localPackageJSON)
+	tarball := filepath.Join(dir, "corp-cli-0.1.0.tgz")
+	writeTarball(t, tarball)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	for _, spec := range []string{"file:" + filepath.Join(dir, "corp-cli"), "file:" + tarball} {
+		pkg, err := manager.ViewPackage(&nodeman.CLIApp{}, spec)
+		assert.Nil(t, err, spec)
+		assert.Equal(t, "corp-cli", pkg.Name, spec)
+		assert.Equal(t, "0.1.0", pkg.Version, spec)
+		assert.Equal(t, ">=18", pkg.Engines["node"], spec)
+		assert.Equal(t, map[string]string{"corp.js": "bin/corp.js"}, pkg.GetBins(), spec)
+	}
+	pkg, err := manager.ViewPackage(&nodeman.CLIApp{}, "file:"+tarball)
+	assert.Nil(t, err)
+	assert.Regexp(t, "^sha512-", pkg.Dist.Integrity)
+	_, err = manager.ViewPackage(&nodeman.CLIApp{}, "file:"+filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestInstallLinkedApp(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(t.TempDir(), "corp-cli")
+	writePackage(t, dir, localPackageJSON)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	pkg, err := manager.ViewPackage(&nodeman.CLIApp{}, "link:"+dir)
+	assert.Nil(t, err)
+
+	prefix, err := manager.InstallApp(&npmNode{t: t}, "link:"+dir, pkg)
+
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(home, ".cli-manager", "apps", "corp-cli", "link"), prefix)
+	installed, err := os.ReadFile(filepath.Join(prefix, "installed"))
+	assert.Nil(t, err)
+	assert.Equal(t, dir, string(installed))
+}
+
+func TestInvalidLocalPackage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+	manager := nodeman.NewManager(afero.NewOsFs())
+	for name, packageJSON := range map[string]string{
+		"traversal":      `{"name": "../../../.ssh", "version": "1.0.0"}`,
+		"scope":          `{"name": "@corp/../../x", "version": "1.0.0"}`,
+		"uppercase":      `{"name": "Corp", "version": "1.0.0"}`,
+		"version":        `{"name": "corp-cli", "version": "../../x"}`,
+		"missingVersion": `{"name": "corp-cli"}`,
+	} {
+		writePackage(t, filepath.Join(dir, name), packageJSON)
+		_, err := manager.ViewPackage(&nodeman.CLIApp{}, "file:"+filepath.Join(dir, name))
+		assert.Error(t, err, name)
+	}
+
+	_, err := manager.InstallApp(&npmNode{t: t}, "file:"+dir, &nodeman.NpmViewResponse{Name: "../../../.ssh", Version: "1.0.0"})
+	assert.ErrorContains(t, err, "not a valid npm package name")
+	_, err = os.Stat(filepath.Join(home, ".cli-manager", "apps"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLocalAppStatus(t *testing.T) {
+	dir := t.TempDir()
+	writePackage(t, dir, localPackageJSON)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	app := &nodeman.CLIApp{App: "corp-cli", InstallName: "file:" + dir, Version: "0.1.0"}
+
+	assert.False(t, manager.GetAppStatus(app).Outdated)
+
+	writePackage(t, dir, `{"name": "corp-cli", "version": "0.2.0"}`)
+	status := manager.GetAppStatus(app)
+	assert.True(t, status.Outdated)
+	assert.Equal(t, "0.2.0", status.Wanted)
+
+	app.InstallName = "link:" + dir
+	assert.False(t, manager.GetAppStatus(app).Outdated)
+}
@@ -1,7 +1,6 @@
 package nodeman
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,12 +8,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/rdaniels6813/cli-manager/internal/gitsource"
+	"github.com/rdaniels6813/cli-manager/internal/registry"
 	"github.com/rdaniels6813/cli-manager/internal/util"
 	"github.com/spf13/afero"
-
-	"github.com/mholt/archiver"
 )
 
 const windows = "windows"
@@ -22,30 +23,141 @@ const windows = "windows"
 // Manager struct for managing node binaries leveraged by the cli manager
 // Use NewManager to create an instance of this object
 type Manager struct {
-	os afero.Fs
+	os             afero.Fs
+	dist           *Dist
+	releaseKeyring string
+	progress       io.Writer
+	interactive    bool
+	generations    int
+	registry       *registry.Client
+	npmrc          func(path string) error
+	git            *gitsource.Resolver
 }
 
+// DefaultGenerations how many previous versions of each app are kept by default
+const DefaultGenerations = 2
+
 // NewManager constructor for default manager with the specified node version
-func NewManager(os afero.Fs) *Manager {
-	return &Manager{os: os}
+func NewManager(os afero.Fs, options ...func(*Manager)) *Manager {
+	manager := &Manager{os: os, dist: NewDist(http.DefaultClient), generations: DefaultGenerations,
+		git: &gitsource.Resolver{Token: githubToken}}
+	for _, option := range options {
+		option(manager)
+	}
+	return manager
+}
+
+// WithDist sets the distribution node releases are downloaded from
+func WithDist(dist *Dist) func(*Manager) {
+	return func(m *Manager) {
+		m.dist = dist
+	}
+}
+
+// WithReleaseKeyring enables verifying the signature of SHASUMS256.txt against the armored
+// node release keyring at the given path, or the keys built into cli-manager when it does not exist
+func WithReleaseKeyring(path string) func(*Manager) {
+	return func(m *Manager) {
+		m.releaseKeyring = path
+	}
+}
+
+// WithProgress reports download progress to out, as a progress bar when interactive
+// or as plain status lines otherwise. Without this option downloads are quiet.
+func WithProgress(out io.Writer, interactive bool) func(*Manager) {
+	return func(m *Manager) {
+		m.progress = out
+		m.interactive = interactive
+	}
+}
+
+// WithGenerations sets how many previous versions of each app are kept on disk for rollback
+func WithGenerations(generations int) func(*Manager) {
+	return func(m *Manager) {
+		m.generations = generations
+	}
 }
 
-// CLIApp config for an installed CLI app
+// WithRegistry reads package metadata from the registry directly instead of running npm view
+func WithRegistry(client *registry.Client) func(*Manager) {
+	return func(m *Manager) {
+		m.registry = client
+	}
+}
+
+// WithNpmrc runs npm with an npmrc written by write as its userconfig, which only exists while npm runs
+func WithNpmrc(write func(path string) error) func(*Manager) {
+	return func(m *Manager) {
+		m.npmrc = write
+	}
+}
+
+// GetReleaseKeyringPath returns the location of the keyring overriding the built in node release keys
+func GetReleaseKeyringPath(aos afero.Fs) string {
+	return filepath.Join(util.GetCliManagerFolder(aos), "node-release-keys.asc")
+}
+
+// GetNpmrcPath returns where earlier versions kept the npmrc holding the registries added to cli-manager
+func GetNpmrcPath(aos afero.Fs) string {
+	return filepath.Join(util.GetCliManagerFolder(aos), "npmrc")
+}
+
+// CLIApp config for an installed CLI app, Path is the bin folder of the node version
+// running the app and Prefix the npm prefix the app is installed into
 type CLIApp struct {
-	App         string `json:"app"`
-	Bin         string `json:"bin"`
-	Path        string `json:"path"`
-	InstallName string `json:"install_name"`
+	App         string    `json:"app"`
+	InstallName string    `json:"install_name"`
+	Version     string    `json:"version,omitempty"`
+	Resolved    string    `json:"resolved,omitempty"`
+	Integrity   string    `json:"integrity,omitempty"`
+	Commit      string    `json:"commit,omitempty"`
+	NodeVersion string    `json:"node_version,omitempty"`
+	Path        string    `json:"path"`
+	Prefix      string    `json:"prefix,omitempty"`
+	NodePolicy  string    `json:"node_policy,omitempty"`
+	NodeRange   string    `json:"node_range,omitempty"`
+	Registry    string    `json:"registry,omitempty"`
+	Flags       []string  `json:"flags,omitempty"`
+	InstalledAt time.Time `json:"installed_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	Bins        []string  `json:"bins"`
+	// Aliases extra command names for bins, keyed by alias
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Previous the earlier versions still on disk, newest first
+	Previous []*CLIApp `json:"previous,omitempty"`
 }
 
 // GetInstalledExecutables list all of the installed app executables
 func (m *Manager) GetInstalledExecutables() []string {
-	configPath := m.getConfigPath()
-	apps := loadConfig(configPath)
-	result := make([]string, 0, len(apps))
-	for app := range apps {
+	apps := m.loadApps()
+	result := []string{}
+	for _, app := range apps {
+		result = append(result, app.Bins...)
+	}
+	return result
+}
+
+// GetAliases returns the aliases of every installed app, mapped to the bins they run
+func (m *Manager) GetAliases() map[string]string {
+	result := map[string]string{}
+	for _, app := range m.loadApps() {
+		for alias, bin := range app.Aliases {
+			result[alias] = bin
+		}
+	}
+	return result
+}
+
+// GetCLIApps lists the installed apps sorted by name
+func (m *Manager) GetCLIApps() []*CLIApp {
+	apps := m.loadApps()
+	result := make([]*CLIApp, 0, len(apps))
+	for _, app := range apps {
 		result = append(result, app)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].App < result[j].App
+	})
 	return result
 }
 
@@ -53,28 +165,42 @@ func (m *Manager) GetInstalledExecutables() []string {
 func (m *Manager) GetNode(version string) (Node, error) {
 	destinationPath := m.getNodeOutputFolder(version)
 	if _, err := m.os.Stat(destinationPath); os.IsNotExist(err) {
+		if m.dist.Offline {
+			return nil, fmt.Errorf("node v%s is not downloaded and cannot be downloaded offline", version)
+		}
 		archivePath, err := m.downloadNodeArchive(version)
 		if err != nil {
 			return nil, err
 		}
-		m.unpackNodeArchive(archivePath, version)
+		err = m.verifyNodeArchive(version, archivePath)
+		if err != nil {
+			return nil, err
+		}
+		err = m.unpackNodeArchive(archivePath, version)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return newNode(destinationPath), nil
+	return m.configureNpm(newNode(destinationPath)), nil
 }
 
 // GetNodeByPath uses the binpath to set up a node helper
 func (m *Manager) GetNodeByPath(path string) Node {
-	return newNode(path)
+	return m.configureNpm(newNode(path))
+}
+
+func (m *Manager) configureNpm(node Node) Node {
+	if m.npmrc == nil {
+		return node
+	}
+	return &npmrcNode{node: node, folder: util.GetCliManagerFolder(m.os), write: m.npmrc}
 }
 
 // GetCLIApp gets the configuration for the cli app by name
 func (m *Manager) GetCLIApp(appName string) (*CLIApp, error) {
-	configPath := m.getConfigPath()
-	apps := loadConfig(configPath)
-	for _, app := range apps {
-		if app.App == appName {
-			return app, nil
-		}
+	apps := m.loadApps()
+	if app, ok := apps[appName]; ok {
+		return app, nil
 	}
 	for _, app := range apps {
 		if app.InstallName == appName {
@@ -85,37 +211,23 @@ func (m *Manager) GetCLIApp(appName string) (*CLIApp, error) {
 }
 
 func (m *Manager) getNodeURL(version string) string {
-	extension := ".tar.xz"
-	os := runtime.GOOS
-	arch := runtime.GOARCH
-	if os == windows {
-		os = "win"
-		extension = ".zip"
-	}
-	if os == "darwin" {
-		extension = ".tar.gz"
-	}
-	if arch == "amd64" {
-		arch = "x64"
-	}
-	return fmt.Sprintf("https://nodejs.org/dist/v%s/node-v%s-%s-%s%s", version, version, os, arch, extension)
+	return m.dist.archiveURL(version)
+}
+
+func (m *Manager) getNodeReleaseURL(version string) string {
+	return m.dist.releaseURL(version)
 }
 
 // GetCommandPath gets the path to the installed command
 func (m *Manager) GetCommandPath(bin string) (string, error) {
-	cliManagerDir := util.GetCliManagerFolder(m.os)
-	installedAppsJSON := filepath.Join(cliManagerDir, "installed.json")
-	config := loadConfig(installedAppsJSON)
-
-	for k, v := range config {
-		if k == bin {
-			if runtime.GOOS == windows {
-				return filepath.Join(v.Path, fmt.Sprintf("%s.cmd", k)), nil
-			}
-			return filepath.Join(v.Path, k), nil
-		}
+	app, ok := findAppByBin(m.loadApps(), bin)
+	if !ok {
+		return "", fmt.Errorf("%s is not installed", bin)
 	}
-	return "", fmt.Errorf("%s is not installed", bin)
+	if runtime.GOOS == windows {
+		return filepath.Join(app.BinPath(), fmt.Sprintf("%s.cmd", bin)), nil
+	}
+	return filepath.Join(app.BinPath(), bin), nil
 }
 
 // ConfigureNodeOnCommand configures a command to use the appropriate PATH
@@ -139,16 +251,11 @@ func (m *Manager) ConfigureNodeOnCommand(command string, cmd *exec.Cmd) {
 
 // GetCommandNodeBinPath returns the bin folder path to the node installation being used for the command
 func (m *Manager) GetCommandNodeBinPath(bin string) (string, error) {
-	cliManagerDir := util.GetCliManagerFolder(m.os)
-	installedAppsJSON := filepath.Join(cliManagerDir, "installed.json")
-	config := loadConfig(installedAppsJSON)
-
-	for k, v := range config {
-		if k == bin {
-			return v.Path, nil
-		}
+	app, ok := findAppByBin(m.loadApps(), bin)
+	if !ok {
+		return "", fmt.Errorf("%s is not installed", bin)
 	}
-	return "", fmt.Errorf("%s is not installed", bin)
+	return app.Path, nil
 }
 
 func (m *Manager) getConfigPath() string {
@@ -156,53 +263,78 @@ func (m *Manager) getConfigPath() string {
 	return filepath.Join(cliManagerDir, "installed.json")
 }
 
-// MarkUninstalled marks the current binaries installed with their paths
+// MarkUninstalled removes the record of the app
 func (m *Manager) MarkUninstalled(appName string) error {
-	installedAppsJSON := m.getConfigPath()
-	config := loadConfig(installedAppsJSON)
-	for bin, app := range config {
-		if app.App == appName {
-			delete(config, bin)
+	apps := m.loadApps()
+	delete(apps, appName)
+	return m.saveApps(apps)
+}
+
+// MarkInstalled records the app with its bins, replacing its previous record
+// and refusing bins another app already provides
+func (m *Manager) MarkInstalled(app *CLIApp, bins map[string]string) error {
+	apps := m.loadApps()
+	for bin := range bins {
+		if existing, ok := findAppByBin(apps, bin); ok && existing.App != app.App {
+			return fmt.Errorf("%s cannot be installed, its %s command is already provided by %s", app.App, bin, existing.App)
 		}
 	}
-	return saveConfig(installedAppsJSON, config)
+	entry := *app
+	entry.Bins = make([]string, 0, len(bins))
+	for bin := range bins {
+		entry.Bins = append(entry.Bins, bin)
+	}
+	sort.Strings(entry.Bins)
+	err := checkAliases(&entry, entry.Aliases)
+	if err != nil {
+		return err
+	}
+	if entry.NodeVersion == "" {
+		entry.NodeVersion, _ = nodeVersionOf(m.getNodeBaseFolder(), entry.Path)
+	}
+	entry.touch(apps[app.App])
+	entry.Previous = m.keepGenerations(apps[app.App], entry.Prefix)
+	apps[app.App] = &entry
+	return m.saveApps(apps)
 }
 
-// MarkInstalled marks the current binaries installed with their paths
-func (m *Manager) MarkInstalled(app string, bins map[string]string, binpath string, installName string) error {
-	installedAppsJSON := m.getConfigPath()
-	config := loadConfig(installedAppsJSON)
-	for k := range bins {
-		config[k] = &CLIApp{Path: binpath, Bin: k, App: app, InstallName: installName}
+// PinNodeRange records the node version range the app is pinned to, an empty range unpins it
+func (m *Manager) PinNodeRange(appName string, nodeRange string) error {
+	apps := m.loadApps()
+	app, ok := apps[appName]
+	if !ok {
+		return fmt.Errorf("App is not installed: %s", appName)
 	}
-	return saveConfig(installedAppsJSON, config)
+	app.NodeRange = nodeRange
+	return m.saveApps(apps)
 }
 
-func saveConfig(path string, config map[string]*CLIApp) error {
-	f, err := os.Create(path)
-	if err != nil {
-		return err
+// SetAliases replaces the aliases of the app
+func (m *Manager) SetAliases(appName string, aliases map[string]string) error {
+	apps := m.loadApps()
+	app, ok := apps[appName]
+	if !ok {
+		return fmt.Errorf("App is not installed: %s", appName)
 	}
-	defer f.Close()
-	err = json.NewEncoder(f).Encode(config)
+	err := checkAliases(app, aliases)
 	if err != nil {
 		return err
 	}
-	return nil
+	app.Aliases = aliases
+	return m.saveApps(apps)
 }
 
-func loadConfig(path string) map[string]*CLIApp {
-	f, err := os.Open(path)
-	if err != nil {
-		return make(map[string]*CLIApp)
-	}
-	defer f.Close()
-	var result map[string]*CLIApp
-	err = json.NewDecoder(f).Decode(&result)
-	if err != nil {
-		return make(map[string]*CLIApp)
+// checkAliases makes sure every alias is a plain command name running one of the app's bins
+func checkAliases(app *CLIApp, aliases map[string]string) error {
+	for alias, bin := range aliases {
+		if !util.IsCommandName(alias) {
+			return fmt.Errorf("Invalid alias for %s: %q", app.App, alias)
+		}
+		if !app.HasBin(bin) {
+			return fmt.Errorf("The alias %s runs %s, which is not a command of %s", alias, bin, app.App)
+		}
 	}
-	return result
+	return nil
 }
 
 func (m *Manager) getNodeBaseFolder() string {
@@ -217,66 +349,7 @@ func (m *Manager) getNodeBaseFolder() string {
 	return nodeFolder
 }
 
-func (m *Manager) downloadNodeArchive(version string) (string, error) {
-	nodeBaseFolder := m.getNodeBaseFolder()
-	// Create the file
-	nodeBinaryPath := filepath.Join(nodeBaseFolder, filepath.Base(m.getNodeURL(version)))
-	if _, err := m.os.Stat(nodeBinaryPath); os.IsNotExist(err) {
-		out, err := os.Create(nodeBinaryPath)
-		if err != nil {
-			return "", fmt.Errorf("Failed to create destination file for node binary: %w", err)
-		}
-		defer out.Close()
-
-		// Get the data
-		resp, err := http.Get(m.getNodeURL(version))
-		if err != nil {
-			return "", fmt.Errorf("Failed download node binary: %w", err)
-		}
-		defer resp.Body.Close()
-
-		// Write the body to file
-		_, err = io.Copy(out, resp.Body)
-		if err != nil {
-			return "", fmt.Errorf("Failed write node binary: %w", err)
-		}
-	}
-	return nodeBinaryPath, nil
-}
 func (m *Manager) getNodeOutputFolder(version string) string {
 	nodeFolder := m.getNodeBaseFolder()
 	return filepath.Join(nodeFolder, version)
 }
-
-func (m *Manager) unpackNodeArchive(path string, version string) {
-	outputFolder := m.getNodeOutputFolder(version)
-	err := archiver.Unarchive(path, outputFolder)
-	if err != nil {
-		fmt.Printf("Failed to unarchive: %s", err)
-		os.Exit(1)
-	}
-	dirs, err := os.ReadDir(outputFolder)
-	if err != nil {
-		fmt.Printf("Failed to read dir: %v", err)
-		os.Exit(1)
-	}
-	dirName := filepath.Join(outputFolder, dirs[0].Name())
-	tmpPath, err := os.MkdirTemp(m.getNodeBaseFolder(), version)
-	os.Remove(tmpPath)
-	if err != nil {
-		fmt.Printf("Failed to get temp dir: %v", err)
-		os.Exit(1)
-	}
-	err = os.Rename(dirName, tmpPath)
-	if err != nil {
-		fmt.Printf("Failed to rename dir: %v", err)
-		os.Exit(1)
-	}
-	os.Remove(outputFolder)
-	err = os.Rename(tmpPath, outputFolder)
-	if err != nil {
-		fmt.Printf("Failed to rename archive paths: %s", err)
-		os.Exit(1)
-	}
-	os.Remove(path)
-}
@@ -1,18 +1,15 @@
 package nodeman
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 
-	"github.com/rdaniels6813/cli-manager/internal/store"
-	"github.com/rdaniels6813/cli-manager/internal/token"
+	"github.com/rdaniels6813/cli-manager/internal/npmrange"
 )
 
 const WINDOWS = "windows"
@@ -23,10 +20,12 @@ type Node interface {
 	Npm(args ...string) error
 	NpmView(packageString string) (*NpmViewResponse, error)
 	BinPath() string
+	WithNpmConfig(key string, value string) Node
 }
 
 type nodeImpl struct {
-	nodePath string
+	nodePath  string
+	npmConfig []string
 }
 
 // Node execute a command using the node binary with the following arguments `node args[0] args[1] ...`
@@ -42,8 +41,36 @@ func (n *nodeImpl) Npm(args ...string) error {
 // NpmViewResponse response from npm view command
 type NpmViewResponse struct {
 	Name    string            `json:"name"`
+	Version string            `json:"version"`
 	Engines map[string]string `json:"engines"`
 	Bin     interface{}       `json:"bin"`
+	Dist    PackageDist       `json:"dist"`
+	// Versions & DistTags describe the whole package, Deprecated only the version viewed
+	Versions   []string          `json:"versions"`
+	DistTags   map[string]string `json:"dist-tags"`
+	Deprecated string            `json:"deprecated"`
+	// Commit the commit a git source resolved to
+	Commit string `json:"-"`
+}
+
+// PackageDist where the registry serves the package tarball from
+type PackageDist struct {
+	Tarball   string `json:"tarball"`
+	Integrity string `json:"integrity"`
+}
+
+// SupportsNode reports whether the node version satisfies the package's engines.node range,
+// packages without one support every version
+func (n *NpmViewResponse) SupportsNode(version string) (bool, error) {
+	engine := n.Engines["node"]
+	if strings.TrimSpace(engine) == "" {
+		return true, nil
+	}
+	versionRange, err := npmrange.Parse(engine)
+	if err != nil {
+		return false, fmt.Errorf("Error parsing the engines.node range of %s: %w", n.Name, err)
+	}
+	return versionRange.ContainsString(version), nil
 }
 
 func (n *NpmViewResponse) GetBins() map[string]string {
@@ -69,72 +96,79 @@ func (n *NpmViewResponse) GetBins() map[string]string {
 func (n *nodeImpl) NpmView(packageString string) (*NpmViewResponse, error) {
 	// This command runs a local node version based on a calculated path.
 	cmd := exec.Command(n.getNpmPath()) //nolint:gosec
+	cmd.Env = append(os.Environ(), n.npmConfig...)
 	cmd.Args = append(cmd.Args, "view", packageString)
 	cmd.Args = append(cmd.Args, "--json")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("Failed to do npm view: %s\nFalling back to github api.", output)
-		return n.githubPackageJSON(packageString)
+		return nil, fmt.Errorf("Failed to do npm view %s: %w\n%s", packageString, err, output)
 	}
 	var response NpmViewResponse
 	err = json.Unmarshal(output, &response)
 	return &response, err
 }
 
-func (n *nodeImpl) githubPackageJSON(packageString string) (*NpmViewResponse, error) {
-	branchIndex := strings.Index(packageString, "#")
-	branch := ""
-	if branchIndex == -1 {
-		branchIndex = len(packageString)
-	} else {
-		branch = packageString[branchIndex+1:]
-	}
-	repoPartialURL := packageString[:branchIndex]
-	packageJSONURL := fmt.Sprintf("https://api.github.com/repos/%s/contents/package.json", repoPartialURL)
-	if branch != "" {
-		packageJSONURL = fmt.Sprintf("%s?ref=%s", packageJSONURL, branch)
-	}
-	var result NpmViewResponse
-	req, err := http.NewRequest("GET", packageJSONURL, nil)
-	if err != nil {
-		return &result, err
-	}
-	manager := token.NewConfigTokenManager(store.GetDefaultStore())
-	token, err := manager.GetNewOrSavedToken([]string{})
-	if err != nil {
-		return nil, err
-	}
-	if token != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return &result, err
-	}
-	if resp.StatusCode != 200 {
-		fmt.Printf("Failed to get package.json for project using: %s, please ensure you have set up your github token",
-			packageJSONURL)
-		os.Exit(1)
-	}
-	defer resp.Body.Close()
-	var content githubContent
-	err = json.NewDecoder(resp.Body).Decode(&content)
-	if err != nil {
-		return nil, err
-	}
-	decoded, err := base64.StdEncoding.DecodeString(content.Content)
+// WithNpmConfig returns a copy of the node helper whose npm commands use the config value,
+// passed as an npm_config_ environment variable so it overrides the user's .npmrc
+func (n *nodeImpl) WithNpmConfig(key string, value string) Node {
+	npmConfig := append(append([]string{}, n.npmConfig...), fmt.Sprintf("npm_config_%s=%s", key, value))
+	return &nodeImpl{nodePath: n.nodePath, npmConfig: npmConfig}
+}
+
+// npmrcNode runs npm with an npmrc written for each command and deleted once npm exits,
+// so registry tokens are only on disk while npm needs them
+type npmrcNode struct {
+	node   Node
+	folder string
+	write  func(path string) error
+}
+
+// Node execute a command using the node binary
+func (n *npmrcNode) Node(args ...string) error {
+	return n.node.Node(args...)
+}
+
+// Npm execute a command using npm with the npmrc as its userconfig
+func (n *npmrcNode) Npm(args ...string) error {
+	return n.withNpmrc(func(node Node) error {
+		return node.Npm(args...)
+	})
+}
+
+// NpmView views the package with the npmrc as npm's userconfig
+func (n *npmrcNode) NpmView(packageString string) (*NpmViewResponse, error) {
+	var response *NpmViewResponse
+	err := n.withNpmrc(func(node Node) error {
+		var err error
+		response, err = node.NpmView(packageString)
+		return err
+	})
+	return response, err
+}
+
+// WithNpmConfig returns a copy of the node helper whose npm commands use the config value
+func (n *npmrcNode) WithNpmConfig(key string, value string) Node {
+	return &npmrcNode{node: n.node.WithNpmConfig(key, value), folder: n.folder, write: n.write}
+}
+
+// BinPath returns the path to the bin directory for the node version
+func (n *npmrcNode) BinPath() string {
+	return n.node.BinPath()
+}
+
+func (n *npmrcNode) withNpmrc(run func(node Node) error) error {
+	f, err := os.CreateTemp(n.folder, ".npmrc-")
 	if err != nil {
-		return nil, err
+		return err
 	}
-	err = json.Unmarshal(decoded, &result)
+	npmrc := f.Name()
+	f.Close()
+	defer os.Remove(npmrc)
+	err = n.write(npmrc)
 	if err != nil {
-		return &result, nil
+		return err
 	}
-	return &result, nil
-}
-
-type githubContent struct {
-	Content string `json:"content"`
+	return run(n.node.WithNpmConfig("userconfig", npmrc))
 }
 
 // BinPath returns the path to the bin directory for the installed node version
@@ -152,7 +186,7 @@ func (n *nodeImpl) command(path string, args ...string) error {
 		}
 		env = append(env, val)
 	}
-	cmd.Env = env
+	cmd.Env = append(env, n.npmConfig...)
 	cmd.Args = append(cmd.Args, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -1,9 +1,14 @@
 package nodeman_test
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,3 +38,46 @@ func TestGetBinsMap(t *testing.T) {
 	assert.Len(t, bins, 1)
 	assert.Equal(t, "./bin/test-app", bins["test-app"])
 }
+
+func TestSupportsNode(t *testing.T) {
+	p := nodeman.NpmViewResponse{Name: "test-app", Engines: map[string]string{"node": ">=14 <18"}}
+	supported, err := p.SupportsNode("16.19.1")
+	assert.Nil(t, err)
+	assert.True(t, supported)
+	supported, err = p.SupportsNode("18.14.0")
+	assert.Nil(t, err)
+	assert.False(t, supported)
+
+	supported, err = (&nodeman.NpmViewResponse{}).SupportsNode("18.14.0")
+	assert.Nil(t, err)
+	assert.True(t, supported)
+
+	_, err = (&nodeman.NpmViewResponse{Engines: map[string]string{"node": ">=x.1"}}).SupportsNode("18.14.0")
+	assert.ErrorContains(t, err, "engines.node")
+}
+
+func TestNpmrcOnlyExistsWhileNpmRuns(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake npm is a shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	bin := filepath.Join(t.TempDir(), "bin")
+	assert.Nil(t, os.MkdirAll(bin, 0700))
+	seen := filepath.Join(t.TempDir(), "seen")
+	script := "#!/bin/sh\necho \"$npm_config_userconfig\" > '" + seen + "'\ncat \"$npm_config_userconfig\" >> '" + seen + "'\n"
+	assert.Nil(t, os.WriteFile(filepath.Join(bin, "npm"), []byte(script), 0700))
+	manager := nodeman.NewManager(afero.NewOsFs(), nodeman.WithNpmrc(func(path string) error {
+		return os.WriteFile(path, []byte("//npm.corp.example.com/:_authToken=secret\n"), 0600)
+	}))
+
+	assert.Nil(t, manager.GetNodeByPath(bin).Npm("install"))
+
+	data, err := os.ReadFile(seen)
+	assert.Nil(t, err)
+	npmrc, content, _ := strings.Cut(string(data), "\n")
+	assert.Equal(t, filepath.Join(home, ".cli-manager"), filepath.Dir(npmrc))
+	assert.Equal(t, "//npm.corp.example.com/:_authToken=secret\n", content)
+	_, err = os.Stat(npmrc)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,154 @@
+package nodeman
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"github.com/rdaniels6813/cli-manager/internal/gitsource"
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/spf13/afero"
+)
+
+// AppStatus how an installed app compares with the versions published to the registry
+type AppStatus struct {
+	App        string `json:"app"`
+	Spec       string `json:"spec"`
+	Installed  string `json:"installed"`
+	Wanted     string `json:"wanted"`
+	Latest     string `json:"latest"`
+	Deprecated string `json:"deprecated,omitempty"`
+	Outdated   bool   `json:"outdated"`
+	Error      string `json:"error,omitempty"`
+}
+
+// GetAppStatus looks up the versions of the app's package published to the registry, the commit
+// the ref of a git source points at or the version in a local folder or tarball
+func (m *Manager) GetAppStatus(app *CLIApp) *AppStatus {
+	status := &AppStatus{App: app.App, Spec: app.InstallName, Installed: m.getInstalledVersion(app)}
+	if localPath, link, ok := parseLocalSpec(app.InstallName); ok {
+		return m.localStatus(localPath, link, status)
+	}
+	if source, ok := gitsource.Parse(app.InstallName); ok {
+		return m.gitStatus(app, source, status)
+	}
+	name, _, ok := registry.ParseSpec(app.InstallName)
+	if !ok {
+		status.Error = "not installed from a registry, git or a local path"
+		return status
+	}
+	pkg, err := m.ViewPackage(app, name)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	if status.Installed != "" {
+		if installed, err := m.ViewPackage(app, fmt.Sprintf("%s@%s", name, status.Installed)); err == nil {
+			status.Deprecated = installed.Deprecated
+		}
+	}
+	status.compare(pkg)
+	return status
+}
+
+// compare fills in the wanted & latest versions from the package document
+func (s *AppStatus) compare(pkg *NpmViewResponse) {
+	s.Latest = pkg.DistTags[registry.LatestTag]
+	name, selector, _ := registry.ParseSpec(s.Spec)
+	wanted, err := registry.ResolveVersion(name, selector, pkg.DistTags, pkg.Versions)
+	if err != nil {
+		s.Error = err.Error()
+		return
+	}
+	s.Wanted = wanted
+	installed, err := semver.ParseTolerant(s.Installed)
+	if err != nil {
+		return
+	}
+	for _, version := range []string{s.Wanted, s.Latest} {
+		if v, err := semver.ParseTolerant(version); err == nil && v.GT(installed) {
+			s.Outdated = true
+		}
+	}
+}
+
+// getInstalledVersion returns the recorded version of the app, reading it from the installed
+// package.json for apps recorded before versions were
+func (m *Manager) getInstalledVersion(app *CLIApp) string {
+	if app.Version != "" {
+		return app.Version
+	}
+	root := app.Prefix
+	if root == "" {
+		root = filepath.Dir(app.Path)
+		if runtime.GOOS == windows {
+			root = app.Path
+		}
+	}
+	modules := filepath.Join(root, "lib", "node_modules")
+	if runtime.GOOS == windows {
+		modules = filepath.Join(root, "node_modules")
+	}
+	data, err := afero.ReadFile(m.os, filepath.Join(modules, filepath.FromSlash(app.App), "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	return pkg.Version
+}
+
+// ViewPackage returns the metadata of the version a spec installs, read from the registry, git or a local path when possible
+// and otherwise with npm view, run by the node version the app is installed with or else the latest LTS version
+func (m *Manager) ViewPackage(app *CLIApp, spec string) (*NpmViewResponse, error) {
+	if localPath, _, ok := parseLocalSpec(spec); ok {
+		return viewLocalPackage(localPath)
+	}
+	if source, ok := gitsource.Parse(spec); ok {
+		return m.viewGitPackage(source)
+	}
+	if _, _, ok := registry.ParseSpec(spec); ok && m.registry != nil {
+		packument, version, err := m.registry.Resolve(spec, app.Registry)
+		if err == nil {
+			return newNpmViewResponse(packument, version), nil
+		}
+		fmt.Fprintf(os.Stderr, "Failed to read %s from the registry, falling back to npm view: %s\n", spec, err)
+	}
+	var node Node
+	if app.Path != "" {
+		node = m.GetNodeByPath(app.Path)
+	} else {
+		version, err := ResolveNodeVersion(PolicyLTS, "", m.dist)
+		if err != nil {
+			return nil, err
+		}
+		node, err = m.GetNode(version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return app.ConfigureNode(node).NpmView(spec)
+}
+
+func newNpmViewResponse(packument *registry.Packument, version *registry.Version) *NpmViewResponse {
+	versions := packument.VersionList()
+	sort.Strings(versions)
+	return &NpmViewResponse{
+		Name:       version.Name,
+		Version:    version.Version,
+		Engines:    version.Engines,
+		Bin:        version.Bin,
+		Dist:       PackageDist{Tarball: version.Dist.Tarball, Integrity: version.Dist.Integrity},
+		Versions:   versions,
+		DistTags:   packument.DistTags,
+		Deprecated: version.Deprecated,
+	}
+}
@@ -0,0 +1,77 @@
+package nodeman_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+const typescriptPackument = `{
+	"name": "typescript",
+	"dist-tags": {"latest": "5.0.2", "next": "5.1.0-dev.20230401"},
+	"versions": {
+		"4.9.4": {"name": "typescript", "version": "4.9.4", "deprecated": "upgrade to 4.9.5"},
+		"4.9.5": {"name": "typescript", "version": "4.9.5", "bin": {"tsc": "bin/tsc"}},
+		"5.0.2": {"name": "typescript", "version": "5.0.2", "bin": {"tsc": "bin/tsc"}},
+		"5.1.0-dev.20230401": {"name": "typescript", "version": "5.1.0-dev.20230401"}
+	}
+}`
+
+func newRegistryManager(t *testing.T) *nodeman.Manager {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/typescript" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(typescriptPackument))
+	}))
+	t.Cleanup(server.Close)
+	config := registry.NewConfig()
+	config.Registry = server.URL
+	return nodeman.NewManager(afero.NewOsFs(), nodeman.WithRegistry(registry.NewClient(http.DefaultClient, config)))
+}
+
+func TestGetAppStatus(t *testing.T) {
+	manager := newRegistryManager(t)
+
+	status := manager.GetAppStatus(&nodeman.CLIApp{App: "typescript", InstallName: "typescript@4", Version: "4.9.4"})
+
+	assert.Equal(t, &nodeman.AppStatus{
+		App: "typescript", Spec: "typescript@4", Installed: "4.9.4", Wanted: "4.9.5", Latest: "5.0.2",
+		Deprecated: "upgrade to 4.9.5", Outdated: true,
+	}, status)
+}
+
+func TestGetAppStatusUpToDate(t *testing.T) {
+	manager := newRegistryManager(t)
+
+	status := manager.GetAppStatus(&nodeman.CLIApp{App: "typescript", InstallName: "typescript", Version: "5.0.2"})
+
+	assert.False(t, status.Outdated)
+	assert.Equal(t, "5.0.2", status.Wanted)
+	assert.Empty(t, status.Error)
+}
+
+func TestGetAppStatusNotFromRegistry(t *testing.T) {
+	manager := newRegistryManager(t)
+
+	status := manager.GetAppStatus(&nodeman.CLIApp{App: "cli-manager", InstallName: "https://example.com/cli-manager.tgz"})
+
+	assert.Equal(t, "not installed from a registry, git or a local path", status.Error)
+}
+
+func TestViewPackage(t *testing.T) {
+	manager := newRegistryManager(t)
+
+	pkg, err := manager.ViewPackage(&nodeman.CLIApp{}, "typescript@~4.9")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "4.9.5", pkg.Version)
+	assert.Equal(t, map[string]interface{}{"tsc": "bin/tsc"}, pkg.Bin)
+	assert.Equal(t, []string{"4.9.4", "4.9.5", "5.0.2", "5.1.0-dev.20230401"}, pkg.Versions)
+}
@@ -0,0 +1,68 @@
+package nodeman
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+// platform the operating system & architecture naming used by node release archives
+type platform struct {
+	os        string
+	arch      string
+	extension string
+	musl      bool
+}
+
+// nodeArchitectures maps GOARCH values to the architecture names used by node releases
+var nodeArchitectures = map[string]string{
+	"amd64":   "x64",
+	"386":     "x86",
+	"arm":     "armv7l",
+	"arm64":   "arm64",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+}
+
+// muslLoaders glob patterns for the dynamic loader of musl based linux distributions like alpine
+var muslLoaders = []string{"/lib/ld-musl-*.so.1", "/usr/lib/ld-musl-*.so.1"}
+
+func detectPlatform() platform {
+	return newPlatform(runtime.GOOS, runtime.GOARCH, runtime.GOOS == "linux" && isMusl())
+}
+
+func newPlatform(goos string, goarch string, musl bool) platform {
+	p := platform{os: goos, arch: goarch, extension: ".tar.xz", musl: musl}
+	switch goos {
+	case windows:
+		p.os = "win"
+		p.extension = ".zip"
+	case "darwin":
+		p.extension = ".tar.gz"
+	}
+	if arch, ok := nodeArchitectures[goarch]; ok {
+		p.arch = arch
+	}
+	return p
+}
+
+func isMusl() bool {
+	for _, pattern := range muslLoaders {
+		if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// name returns the platform part of a release archive name, e.g. linux-x64 or linux-x64-musl
+func (p platform) name() string {
+	if p.musl {
+		return fmt.Sprintf("%s-%s-musl", p.os, p.arch)
+	}
+	return fmt.Sprintf("%s-%s", p.os, p.arch)
+}
+
+func (p platform) archiveName(version string) string {
+	return fmt.Sprintf("node-v%s-%s%s", version, p.name(), p.extension)
+}
@@ -0,0 +1,97 @@
+package nodeman
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var archiveNames = []struct {
+	goos     string
+	goarch   string
+	musl     bool
+	expected string
+}{
+	{"linux", "amd64", false, "node-v18.14.0-linux-x64.tar.xz"},
+	{"linux", "arm64", false, "node-v18.14.0-linux-arm64.tar.xz"},
+	{"linux", "arm", false, "node-v18.14.0-linux-armv7l.tar.xz"},
+	{"linux", "ppc64le", false, "node-v18.14.0-linux-ppc64le.tar.xz"},
+	{"linux", "s390x", false, "node-v18.14.0-linux-s390x.tar.xz"},
+	{"linux", "amd64", true, "node-v18.14.0-linux-x64-musl.tar.xz"},
+	{"darwin", "arm64", false, "node-v18.14.0-darwin-arm64.tar.gz"},
+	{"windows", "386", false, "node-v18.14.0-win-x86.zip"},
+	{"windows", "amd64", false, "node-v18.14.0-win-x64.zip"},
+}
+
+func TestPlatformArchiveName(t *testing.T) {
+	for _, test := range archiveNames {
+		test := test
+		t.Run(test.expected, func(t *testing.T) {
+			p := newPlatform(test.goos, test.goarch, test.musl)
+			assert.Equal(t, test.expected, p.archiveName("18.14.0"))
+		})
+	}
+}
+
+func TestMuslArchivesUseUnofficialBuilds(t *testing.T) {
+	dist := NewDist(http.DefaultClient, func(d *Dist) {
+		d.platform = newPlatform("linux", "amd64", true)
+	})
+
+	assert.Equal(t,
+		"https://unofficial-builds.nodejs.org/download/release/v18.14.0/node-v18.14.0-linux-x64-musl.tar.xz",
+		dist.archiveURL("18.14.0"))
+	assert.Equal(t, "https://unofficial-builds.nodejs.org/download/release/index.json", dist.indexURL())
+}
+
+func TestMuslArchivesUseMirror(t *testing.T) {
+	dist := NewDist(http.DefaultClient, func(d *Dist) {
+		d.BaseURL = "https://artifactory.example.com/node/"
+		d.platform = newPlatform("linux", "amd64", true)
+	})
+
+	assert.Equal(t, "https://artifactory.example.com/node/index.json", dist.indexURL())
+}
+
+// authRecorder records the Authorization header sent with each request
+type authRecorder map[string]string
+
+func (r authRecorder) Do(req *http.Request) (*http.Response, error) {
+	r[req.URL.String()] = req.Header.Get("Authorization")
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestMirrorCredentialsStayOnMirrorHost(t *testing.T) {
+	recorder := authRecorder{}
+	dist := NewDist(recorder, func(d *Dist) {
+		d.BaseURL = "https://artifactory.example.com/node"
+		d.UnofficialBaseURL = DefaultUnofficialDistURL
+		d.Token = "secret"
+	})
+	for _, url := range []string{
+		"https://artifactory.example.com/node/index.json",
+		"https://unofficial-builds.nodejs.org/download/release/index.json",
+		"https://artifactory.example.com.evil.test/node/index.json",
+	} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err)
+		_, err = dist.Do(req)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, authRecorder{
+		"https://artifactory.example.com/node/index.json":                  "Bearer secret",
+		"https://unofficial-builds.nodejs.org/download/release/index.json": "",
+		"https://artifactory.example.com.evil.test/node/index.json":        "",
+	}, recorder)
+}
+
+func TestGlibcArchivesUseDist(t *testing.T) {
+	dist := NewDist(http.DefaultClient, func(d *Dist) {
+		d.platform = newPlatform("linux", "arm", false)
+	})
+
+	assert.Equal(t, "https://nodejs.org/dist/v18.14.0/node-v18.14.0-linux-armv7l.tar.xz", dist.archiveURL("18.14.0"))
+	assert.Equal(t, "https://nodejs.org/dist/index.json", dist.indexURL())
+}
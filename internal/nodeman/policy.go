@@ -0,0 +1,170 @@
+package nodeman
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/rdaniels6813/cli-manager/internal/npmrange"
+)
+
+// Policy decides which node releases are candidates when choosing a node version for an app
+type Policy string
+
+const (
+	// PolicyLTS the latest release of any LTS line
+	PolicyLTS Policy = "lts"
+	// PolicyActiveLTS the latest release of a line in active LTS
+	PolicyActiveLTS Policy = "active-lts"
+	// PolicyMaintenance the latest release of a line in maintenance LTS
+	PolicyMaintenance Policy = "maintenance"
+	// PolicyCurrent the latest release of any line, including odd numbered ones
+	PolicyCurrent Policy = "current"
+	// PolicyExact exactly the version given as the range
+	PolicyExact Policy = "exact"
+	// DefaultPolicy the policy used when none is configured
+	DefaultPolicy = PolicyLTS
+
+	codenamePrefix = "lts/"
+)
+
+// Policies lists the supported policies, besides the lts/<codename> aliases
+var Policies = []Policy{PolicyLTS, PolicyActiveLTS, PolicyMaintenance, PolicyCurrent, PolicyExact}
+
+// ParsePolicy validates a policy name, an empty name is the default policy
+func ParsePolicy(value string) (Policy, error) {
+	policy := Policy(strings.ToLower(strings.TrimSpace(value)))
+	if policy == "" {
+		return DefaultPolicy, nil
+	}
+	if strings.HasPrefix(string(policy), codenamePrefix) && len(policy) > len(codenamePrefix) {
+		return policy, nil
+	}
+	for _, known := range Policies {
+		if policy == known {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("Unknown node policy %q, expected one of %s or lts/<codename>", value, policiesList())
+}
+
+func policiesList() string {
+	names := make([]string, 0, len(Policies))
+	for _, policy := range Policies {
+		names = append(names, string(policy))
+	}
+	return strings.Join(names, ", ")
+}
+
+// ResolveNodeVersion returns the latest node release allowed by the policy that matches the npm style range
+func ResolveNodeVersion(policy Policy, engine string, dist *Dist) (string, error) {
+	if policy == PolicyExact {
+		return resolveExactVersion(engine, dist)
+	}
+	versionRange, err := npmrange.Parse(engine)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing node version range: %w", err)
+	}
+	allowed, err := policyFilter(policy, dist)
+	if err != nil && !dist.Offline {
+		return "", err
+	}
+	releases, err := getNodeReleases(dist)
+	if err != nil {
+		return "", err
+	}
+	var latest semver.Version
+	found := false
+	if allowed != nil {
+		latest, found, err = latestRelease(*releases, versionRange, allowed)
+		if err != nil {
+			return "", err
+		}
+	}
+	if !found && dist.Offline {
+		// without the cached index & schedule downloaded versions cannot be told apart by policy,
+		// so any of them matching the range will do
+		latest, found, err = latestRelease(*releases, versionRange, anyRelease)
+		if err != nil {
+			return "", err
+		}
+	}
+	if !found {
+		if strings.TrimSpace(engine) == "" {
+			return "", fmt.Errorf("No node release matches the %s policy", policy)
+		}
+		return "", fmt.Errorf("No node release matches the range %s with the %s policy", engine, policy)
+	}
+	return latest.String(), nil
+}
+
+// latestRelease returns the latest of the releases in the range that the policy allows
+func latestRelease(releases []nodeLTSSchedule, versionRange npmrange.Range,
+	allowed func(nodeLTSSchedule, semver.Version) bool) (semver.Version, bool, error) {
+	var latest semver.Version
+	found := false
+	for _, release := range releases {
+		version, err := parseSemver(release.Version)
+		if err != nil {
+			return latest, false, err
+		}
+		if versionRange.Contains(version) && allowed(release, version) && (!found || latest.LT(version)) {
+			latest = version
+			found = true
+		}
+	}
+	return latest, found, nil
+}
+
+func anyRelease(nodeLTSSchedule, semver.Version) bool {
+	return true
+}
+
+func resolveExactVersion(engine string, dist *Dist) (string, error) {
+	version, err := semver.ParseTolerant(strings.TrimPrefix(strings.TrimSpace(engine), "="))
+	if err != nil {
+		return "", fmt.Errorf("The exact policy requires an exact node version, got %q", engine)
+	}
+	releases, err := getNodeReleases(dist)
+	if err != nil {
+		return "", err
+	}
+	for _, release := range *releases {
+		if strings.TrimPrefix(release.Version, "v") == version.String() {
+			return version.String(), nil
+		}
+	}
+	return "", fmt.Errorf("node v%s is not a known release", version)
+}
+
+func policyFilter(policy Policy, dist *Dist) (func(nodeLTSSchedule, semver.Version) bool, error) {
+	switch policy {
+	case PolicyLTS:
+		return func(release nodeLTSSchedule, _ semver.Version) bool {
+			return release.isLTS()
+		}, nil
+	case PolicyCurrent:
+		return anyRelease, nil
+	case PolicyActiveLTS, PolicyMaintenance:
+		schedule, err := getNodeSchedule(dist)
+		if err != nil {
+			return nil, err
+		}
+		phase := PhaseActiveLTS
+		if policy == PolicyMaintenance {
+			phase = PhaseMaintenance
+		}
+		at := now()
+		return func(release nodeLTSSchedule, version semver.Version) bool {
+			line, ok := schedule[fmt.Sprintf("v%d", version.Major)]
+			return ok && release.isLTS() && line.phase(at) == phase
+		}, nil
+	}
+	if codename := strings.TrimPrefix(string(policy), codenamePrefix); codename != string(policy) {
+		return func(release nodeLTSSchedule, _ semver.Version) bool {
+			name, ok := release.LTS.(string)
+			return ok && strings.EqualFold(name, codename)
+		}, nil
+	}
+	return nil, fmt.Errorf("Unknown node policy %q", policy)
+}
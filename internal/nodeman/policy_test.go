@@ -0,0 +1,124 @@
+package nodeman
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixtureClient serves the index.json & schedule.json fixtures
+type fixtureClient struct {
+	t *testing.T
+}
+
+func (c *fixtureClient) Do(req *http.Request) (*http.Response, error) {
+	fixture := "./node-schedule.json"
+	if strings.HasSuffix(req.URL.Path, "/schedule.json") {
+		fixture = "./node-release-schedule.json"
+	}
+	body, err := os.ReadFile(fixture)
+	assert.Nil(c.t, err)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+func setNow(t *testing.T, date string) {
+	at, err := time.Parse("2006-01-02", date)
+	assert.Nil(t, err)
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestScheduleFromMirror(t *testing.T) {
+	var requested []string
+	dist := NewDist(clientFunc(func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.String())
+		return (&fixtureClient{t: t}).Do(req)
+	}), func(d *Dist) {
+		d.ScheduleURL = "https://mirror.example.com/node/schedule.json"
+	})
+
+	schedule, err := getNodeSchedule(dist)
+
+	assert.Nil(t, err)
+	assert.Contains(t, schedule, "v14")
+	assert.Equal(t, []string{"https://mirror.example.com/node/schedule.json"}, requested)
+}
+
+// clientFunc an HTTPClient calling the function
+type clientFunc func(req *http.Request) (*http.Response, error)
+
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var policyTests = []struct {
+	date     string
+	policy   string
+	engine   string
+	expected string
+}{
+	{"2020-11-15", "lts", "", "14.15.0"},
+	{"2020-11-15", "lts", "^12", "12.19.0"},
+	{"2020-11-15", "current", "", "15.0.1"},
+	{"2020-11-15", "active-lts", "", "14.15.0"},
+	{"2020-11-15", "active-lts", "12.x", "12.19.0"},
+	{"2020-12-15", "maintenance", "", "12.19.0"},
+	{"2020-12-15", "maintenance", "10.x", "10.23.0"},
+	{"2020-11-15", "lts/dubnium", "", "10.23.0"},
+	{"2020-11-15", "lts/Erbium", "<12.19", "12.18.4"},
+	{"2020-11-15", "exact", "12.18.0", "12.18.0"},
+	{"2020-11-15", "exact", "v10.0.0", "10.0.0"},
+}
+
+func TestResolveNodeVersion(t *testing.T) {
+	for _, test := range policyTests {
+		test := test
+		t.Run(test.policy+" "+test.engine, func(t *testing.T) {
+			setNow(t, test.date)
+			policy, err := ParsePolicy(test.policy)
+			assert.Nil(t, err)
+
+			version, err := ResolveNodeVersion(policy, test.engine, NewDist(&fixtureClient{t: t}))
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, version)
+		})
+	}
+}
+
+func TestResolveNodeVersionErrors(t *testing.T) {
+	setNow(t, "2020-11-15")
+	dist := NewDist(&fixtureClient{t: t})
+
+	_, err := ResolveNodeVersion(PolicyLTS, ">=15", dist)
+	assert.ErrorContains(t, err, "No node release matches the range >=15 with the lts policy")
+	_, err = ResolveNodeVersion(PolicyExact, "^12", dist)
+	assert.ErrorContains(t, err, "requires an exact node version")
+	_, err = ResolveNodeVersion(PolicyExact, "12.99.0", dist)
+	assert.ErrorContains(t, err, "not a known release")
+	setNow(t, "2019-01-01")
+	_, err = ResolveNodeVersion(PolicyMaintenance, "", dist)
+	assert.ErrorContains(t, err, "No node release matches the maintenance policy")
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy("")
+	assert.Nil(t, err)
+	assert.Equal(t, PolicyLTS, policy)
+
+	policy, err = ParsePolicy("LTS/Hydrogen")
+	assert.Nil(t, err)
+	assert.Equal(t, Policy("lts/hydrogen"), policy)
+
+	_, err = ParsePolicy("stable")
+	assert.ErrorContains(t, err, "Unknown node policy")
+}
@@ -0,0 +1,157 @@
+package nodeman
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// NodeRuntime a node version downloaded into the cli-manager folder
+type NodeRuntime struct {
+	Version string
+	Path    string
+	Size    int64
+	Apps    []string
+}
+
+// GetNodeRuntimes lists the downloaded node versions, with their size on disk and the apps using them
+func (m *Manager) GetNodeRuntimes() ([]*NodeRuntime, error) {
+	nodeBaseFolder := m.getNodeBaseFolder()
+	entries, err := afero.ReadDir(m.os, nodeBaseFolder)
+	if err != nil {
+		return nil, err
+	}
+	usage := m.getNodeUsage(true)
+	result := make([]*NodeRuntime, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := parseSemver(entry.Name()); err != nil {
+			continue
+		}
+		runtimePath := filepath.Join(nodeBaseFolder, entry.Name())
+		size, err := m.dirSize(runtimePath)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &NodeRuntime{
+			Version: entry.Name(),
+			Path:    runtimePath,
+			Size:    size,
+			Apps:    usage[entry.Name()],
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, _ := parseSemver(result[i].Version)
+		b, _ := parseSemver(result[j].Version)
+		return a.LT(b)
+	})
+	return result, nil
+}
+
+// RemoveNode deletes a downloaded node version, refusing if an installed app still uses it,
+// and returns the removed version
+func (m *Manager) RemoveNode(version string) (string, error) {
+	parsed, err := parseSemver(version)
+	if err != nil {
+		return "", fmt.Errorf("Invalid node version %q: %w", version, err)
+	}
+	version = parsed.String()
+	runtimePath := m.getNodeOutputFolder(version)
+	if _, err := m.os.Stat(runtimePath); os.IsNotExist(err) {
+		return "", fmt.Errorf("Node v%s is not installed", version)
+	}
+	if apps := m.getNodeUsage(true)[version]; len(apps) > 0 {
+		return "", fmt.Errorf("Node v%s is used by: %s", version, strings.Join(apps, ", "))
+	}
+	return version, m.os.RemoveAll(runtimePath)
+}
+
+// PruneNodes deletes every downloaded node version no installed app uses, returning the removed versions
+func (m *Manager) PruneNodes() ([]string, error) {
+	runtimes, err := m.GetNodeRuntimes()
+	if err != nil {
+		return nil, err
+	}
+	removed := []string{}
+	for _, nodeRuntime := range runtimes {
+		if len(nodeRuntime.Apps) > 0 {
+			continue
+		}
+		err = m.os.RemoveAll(nodeRuntime.Path)
+		if err != nil {
+			return removed, err
+		}
+		removed = append(removed, nodeRuntime.Version)
+	}
+	return removed, nil
+}
+
+// getNodeUsage maps node versions to the sorted names of the apps installed with them, along with
+// the previous versions kept for rollback when withPrevious is set, so removing a node version keeps them working
+func (m *Manager) getNodeUsage(withPrevious bool) map[string][]string {
+	nodeBaseFolder := m.getNodeBaseFolder()
+	apps := m.loadApps()
+	seen := map[string]map[string]bool{}
+	for _, app := range apps {
+		generations := []*CLIApp{app}
+		if withPrevious {
+			generations = append(generations, app.Previous...)
+		}
+		for _, generation := range generations {
+			version, ok := nodeVersionOf(nodeBaseFolder, generation.Path)
+			if !ok {
+				continue
+			}
+			if seen[version] == nil {
+				seen[version] = map[string]bool{}
+			}
+			seen[version][app.App] = true
+		}
+	}
+	result := make(map[string][]string, len(seen))
+	for version, names := range seen {
+		for name := range names {
+			result[version] = append(result[version], name)
+		}
+		sort.Strings(result[version])
+	}
+	return result
+}
+
+// GetCLIAppNodeVersion returns the version of the node runtime the app is installed with
+func (m *Manager) GetCLIAppNodeVersion(app *CLIApp) (string, error) {
+	version, ok := nodeVersionOf(m.getNodeBaseFolder(), app.Path)
+	if !ok {
+		return "", fmt.Errorf("%s is not installed with a node version managed by cli-manager", app.App)
+	}
+	return version, nil
+}
+
+// nodeVersionOf returns the node version folder under nodeBaseFolder that contains path
+func nodeVersionOf(nodeBaseFolder string, path string) (string, bool) {
+	relative, err := filepath.Rel(nodeBaseFolder, path)
+	if err != nil || relative == "." || strings.HasPrefix(relative, "..") {
+		return "", false
+	}
+	return strings.Split(filepath.ToSlash(relative), "/")[0], true
+}
+
+func (m *Manager) dirSize(path string) (int64, error) {
+	var size int64
+	err := afero.Walk(m.os, path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,121 @@
+package nodeman_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupNodeRuntimes(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cliManagerDir := filepath.Join(home, ".cli-manager")
+	nodeFolder := filepath.Join(cliManagerDir, "node")
+	for _, version := range []string{"18.14.0", "16.19.1"} {
+		assert.Nil(t, os.MkdirAll(filepath.Join(nodeFolder, version, "bin"), 0700))
+		assert.Nil(t, os.WriteFile(filepath.Join(nodeFolder, version, "bin", "node"), make([]byte, 2048), 0700))
+	}
+	assert.Nil(t, os.MkdirAll(filepath.Join(nodeFolder, ".extract-20.0.0-123"), 0700))
+	assert.Nil(t, os.WriteFile(filepath.Join(nodeFolder, "node-v20.0.0-linux-x64.tar.xz.part"), []byte("partial"), 0600))
+	manager := nodeman.NewManager(afero.NewOsFs())
+	assert.Nil(t, manager.MarkInstalled(&nodeman.CLIApp{
+		App: "@angular/cli", Path: filepath.Join(nodeFolder, "18.14.0", "bin"), InstallName: "@angular/cli",
+	}, map[string]string{"ng": "bin/ng"}))
+	assert.Nil(t, manager.MarkInstalled(&nodeman.CLIApp{
+		App: "typescript", Path: filepath.Join(nodeFolder, "18.14.0", "bin"), InstallName: "typescript",
+	}, map[string]string{"tsc": "bin/tsc", "tsserver": "bin/tsserver"}))
+	return nodeFolder
+}
+
+func TestGetNodeRuntimes(t *testing.T) {
+	nodeFolder := setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	runtimes, err := manager.GetNodeRuntimes()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*nodeman.NodeRuntime{
+		{Version: "16.19.1", Path: filepath.Join(nodeFolder, "16.19.1"), Size: 2048},
+		{Version: "18.14.0", Path: filepath.Join(nodeFolder, "18.14.0"), Size: 2048, Apps: []string{"@angular/cli", "typescript"}},
+	}, runtimes)
+}
+
+func TestRemoveNodeRefusesWhenUsed(t *testing.T) {
+	nodeFolder := setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	_, err := manager.RemoveNode("v18.14.0")
+
+	assert.ErrorContains(t, err, "used by: @angular/cli, typescript")
+	assert.DirExists(t, filepath.Join(nodeFolder, "18.14.0"))
+}
+
+func TestRemoveNode(t *testing.T) {
+	nodeFolder := setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	version, err := manager.RemoveNode("v16.19.1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "16.19.1", version)
+	assert.NoDirExists(t, filepath.Join(nodeFolder, "16.19.1"))
+	_, err = manager.RemoveNode("16.19.1")
+	assert.ErrorContains(t, err, "Node v16.19.1 is not installed")
+}
+
+func TestRemoveNodeRejectsInvalidVersion(t *testing.T) {
+	nodeFolder := setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	for _, version := range []string{"../..", "..", "16.19.1/../18.14.0", ""} {
+		_, err := manager.RemoveNode(version)
+		assert.ErrorContains(t, err, "Invalid node version")
+	}
+	assert.DirExists(t, filepath.Join(nodeFolder, "16.19.1"))
+	assert.DirExists(t, filepath.Join(nodeFolder, "18.14.0"))
+}
+
+func TestPruneNodes(t *testing.T) {
+	nodeFolder := setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	removed, err := manager.PruneNodes()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"16.19.1"}, removed)
+	assert.NoDirExists(t, filepath.Join(nodeFolder, "16.19.1"))
+	assert.DirExists(t, filepath.Join(nodeFolder, "18.14.0"))
+}
+
+func TestPinNodeRange(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+
+	assert.Nil(t, manager.PinNodeRange("typescript", "^16"))
+
+	app, err := manager.GetCLIApp("typescript")
+	assert.Nil(t, err)
+	assert.Equal(t, "^16", app.NodeRange)
+	app, err = manager.GetCLIApp("@angular/cli")
+	assert.Nil(t, err)
+	assert.Equal(t, "", app.NodeRange)
+	assert.ErrorContains(t, manager.PinNodeRange("eslint", "^16"), "not installed")
+}
+
+func TestGetCLIAppNodeVersion(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	app, err := manager.GetCLIApp("typescript")
+	assert.Nil(t, err)
+
+	version, err := manager.GetCLIAppNodeVersion(app)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "18.14.0", version)
+	_, err = manager.GetCLIAppNodeVersion(&nodeman.CLIApp{App: "gh", Path: "/usr/local/bin"})
+	assert.ErrorContains(t, err, "not installed with a node version managed by cli-manager")
+}
@@ -0,0 +1,73 @@
+package nodeman
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const scheduleCacheFile = "schedule.json"
+
+// Release phases of a node major version
+const (
+	PhasePending     = "pending"
+	PhaseCurrent     = "current"
+	PhaseActiveLTS   = "active-lts"
+	PhaseMaintenance = "maintenance"
+	PhaseEndOfLife   = "end-of-life"
+)
+
+// nodeReleaseLine an entry of the node release schedule for a major version
+type nodeReleaseLine struct {
+	Start       string `json:"start"`
+	LTS         string `json:"lts"`
+	Maintenance string `json:"maintenance"`
+	End         string `json:"end"`
+	Codename    string `json:"codename"`
+}
+
+// now is replaced in tests to evaluate the schedule at a fixed date
+var now = time.Now
+
+// phase returns the phase the major version is in at the given time
+func (l nodeReleaseLine) phase(at time.Time) string {
+	switch {
+	case isOnOrAfter(at, l.End):
+		return PhaseEndOfLife
+	case isOnOrAfter(at, l.Maintenance):
+		return PhaseMaintenance
+	case isOnOrAfter(at, l.LTS):
+		return PhaseActiveLTS
+	case isOnOrAfter(at, l.Start):
+		return PhaseCurrent
+	}
+	return PhasePending
+}
+
+func isOnOrAfter(at time.Time, date string) bool {
+	if date == "" {
+		return false
+	}
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return false
+	}
+	return !at.Before(parsed)
+}
+
+// getNodeSchedule returns the release schedule keyed by major version, e.g. v18
+func getNodeSchedule(dist *Dist) (map[string]nodeReleaseLine, error) {
+	if dist.schedule == nil {
+		body, err := dist.fetchCached(dist.ScheduleURL, scheduleCacheFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the node release schedule: %w", err)
+		}
+		var schedule map[string]nodeReleaseLine
+		err = json.Unmarshal(body, &schedule)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the node release schedule: %w", err)
+		}
+		dist.schedule = schedule
+	}
+	return dist.schedule, nil
+}
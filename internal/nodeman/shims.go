@@ -0,0 +1,104 @@
+package nodeman
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/rdaniels6813/cli-manager/internal/util"
+	"github.com/spf13/afero"
+)
+
+// shimMarker identifies the files in the shim folder written by cli-manager
+const shimMarker = "cli-manager shim"
+
+// GetShimFolder returns the folder holding an executable shim per installed bin, which belongs on PATH
+func GetShimFolder(aos afero.Fs) string {
+	return filepath.Join(util.GetCliManagerFolder(aos), "bin")
+}
+
+// WriteShims writes the shims of the installed apps
+func (m *Manager) WriteShims() error {
+	return m.writeShims(m.loadApps())
+}
+
+// writeShims makes the shim folder hold a shim for every bin & alias of the apps, removing the shims
+// of bins that are no longer installed
+func (m *Manager) writeShims(apps map[string]*CLIApp) error {
+	folder := GetShimFolder(m.os)
+	err := m.os.MkdirAll(folder, 0700)
+	if err != nil {
+		return err
+	}
+	shims := map[string]string{}
+	for _, app := range apps {
+		for _, bin := range app.Bins {
+			shims[shimName(bin)] = m.shimScript(app, bin)
+		}
+		for alias, bin := range app.Aliases {
+			if !util.IsCommandName(bin) {
+				fmt.Fprintf(os.Stderr, "Skipped the shim for the alias %s of %s, %q is not a valid command name\n", alias, app.App, bin)
+				continue
+			}
+			shims[shimName(alias)] = m.shimScript(app, bin)
+		}
+	}
+	for name, script := range shims {
+		path := filepath.Join(folder, name)
+		if !util.IsCommandName(strings.TrimSuffix(name, ".cmd")) || !isWithin(folder, path) {
+			fmt.Fprintf(os.Stderr, "Skipped the shim for %q, it is not a valid command name\n", name)
+			continue
+		}
+		if current, err := afero.ReadFile(m.os, path); err == nil && string(current) == script {
+			continue
+		}
+		err = util.WriteFileAtomic(m.os, path, []byte(script), 0755)
+		if err != nil {
+			return err
+		}
+	}
+	entries, err := afero.ReadDir(m.os, folder)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(folder, entry.Name())
+		if _, ok := shims[entry.Name()]; ok || !m.isShim(path) {
+			continue
+		}
+		err = m.os.Remove(path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func shimName(bin string) string {
+	if runtime.GOOS == windows {
+		return bin + ".cmd"
+	}
+	return bin
+}
+
+// shimScript runs the bin with the app's node version first on PATH
+func (m *Manager) shimScript(app *CLIApp, bin string) string {
+	if runtime.GOOS == windows {
+		return fmt.Sprintf("@echo off\r\nrem %s for %s\r\nset \"PATH=%s;%%PATH%%\"\r\n\"%s\" %%*\r\n",
+			shimMarker, app.App, app.Path, filepath.Join(app.BinPath(), bin+".cmd"))
+	}
+	return fmt.Sprintf("#!/bin/sh\n# %s for %s\nexport PATH=%s:\"$PATH\"\nexec %s \"$@\"\n",
+		shimMarker, app.App, shellQuote(app.Path), shellQuote(filepath.Join(app.BinPath(), bin)))
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// isShim reports whether the file was written by cli-manager, so other files in the folder are left alone
+func (m *Manager) isShim(path string) bool {
+	data, err := afero.ReadFile(m.os, path)
+	return err == nil && strings.Contains(string(data), shimMarker)
+}
@@ -0,0 +1,96 @@
+package nodeman_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/nodeman"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShims(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shims are cmd files on windows")
+	}
+	nodeFolder := setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	shimFolder := nodeman.GetShimFolder(afero.NewOsFs())
+	assert.Nil(t, os.WriteFile(filepath.Join(shimFolder, "other"), []byte("#!/bin/sh\n"), 0700))
+
+	for _, bin := range []string{"ng", "tsc", "tsserver"} {
+		info, err := os.Stat(filepath.Join(shimFolder, bin))
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	}
+	assert.Nil(t, manager.SetAliases("typescript", map[string]string{"ts": "tsc"}))
+	data, err := os.ReadFile(filepath.Join(shimFolder, "ts"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), filepath.Join(nodeFolder, "18.14.0", "bin", "tsc"))
+
+	// the shim runs the bin with the app's node folder first on PATH
+	bin := filepath.Join(nodeFolder, "18.14.0", "bin", "tsc")
+	assert.Nil(t, os.WriteFile(bin, []byte("#!/bin/sh\necho \"${PATH%%:*}\" \"$@\"\n"), 0700))
+	output, err := exec.Command(filepath.Join(shimFolder, "tsc"), "--version").Output()
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(nodeFolder, "18.14.0", "bin")+" --version\n", string(output))
+
+	assert.Nil(t, manager.MarkUninstalled("typescript"))
+	for _, bin := range []string{"tsc", "tsserver", "ts"} {
+		_, err = os.Stat(filepath.Join(shimFolder, bin))
+		assert.True(t, os.IsNotExist(err))
+	}
+	_, err = os.Stat(filepath.Join(shimFolder, "ng"))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(shimFolder, "other"))
+	assert.Nil(t, err)
+}
+
+func TestWriteShimsRestoresMissingShims(t *testing.T) {
+	setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	shimFolder := nodeman.GetShimFolder(afero.NewOsFs())
+	assert.Nil(t, os.RemoveAll(shimFolder))
+
+	assert.Nil(t, manager.WriteShims())
+
+	for _, bin := range []string{"ng", "tsc", "tsserver"} {
+		data, err := os.ReadFile(filepath.Join(shimFolder, shimFile(bin)))
+		assert.Nil(t, err)
+		assert.Contains(t, string(data), "cli-manager shim")
+	}
+}
+
+func shimFile(bin string) string {
+	if runtime.GOOS == "windows" {
+		return bin + ".cmd"
+	}
+	return bin
+}
+
+func TestShimsSkipUnsafeBins(t *testing.T) {
+	nodeFolder := setupNodeRuntimes(t)
+	manager := nodeman.NewManager(afero.NewOsFs())
+	home, err := os.UserHomeDir()
+	assert.Nil(t, err)
+
+	assert.Nil(t, manager.MarkInstalled(&nodeman.CLIApp{
+		App: "evil", Path: filepath.Join(nodeFolder, "18.14.0", "bin"), InstallName: "evil",
+	}, map[string]string{"../../.profile": "bin/a", "x';touch pwned;'": "bin/b", "evil": "bin/evil"}))
+
+	assert.NoFileExists(t, filepath.Join(home, ".profile"))
+	entries, err := os.ReadDir(nodeman.GetShimFolder(afero.NewOsFs()))
+	assert.Nil(t, err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{shimFile("evil"), shimFile("ng"), shimFile("tsc"), shimFile("tsserver")}, names)
+	script := manager.AliasesScript(nodeman.ZshAliasesFile)
+	assert.Contains(t, script, "alias evil='cli-manager run evil'\n")
+	assert.NotContains(t, script, "pwned")
+	assert.NotContains(t, script, ".profile")
+}
@@ -17,15 +17,22 @@ type clientMock struct {
 
 func (c *clientMock) Do(req *http.Request) (*http.Response, error) {
 	return &http.Response{
-		Body: io.NopCloser(bytes.NewReader(defaultBody)),
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(defaultBody)),
 	}, nil
 }
 
 var tests = map[string]string{
-	">=10.x <14.x": "12.19.0",
-	"10.x":         "10.23.0",
-	">=10.x":       "14.15.0",
-	"12.x":         "12.19.0",
+	">=10.x <14.x":          "12.19.0",
+	"10.x":                  "10.23.0",
+	">=10.x":                "14.15.0",
+	"12.x":                  "12.19.0",
+	"^12.0.0":               "12.19.0",
+	"~12.18":                "12.18.4",
+	"^10.13.0 || ^12.13.0":  "12.19.0",
+	"10 - 12.18":            "12.18.4",
+	">= 10.13.0 < 13":       "12.19.0",
+	"^14.0.0 || >=15.0.0-0": "14.15.0",
 }
 
 func getNodeSchedule() []byte {
@@ -39,14 +46,25 @@ func getNodeSchedule() []byte {
 
 var defaultBody = getNodeSchedule()
 
-func TestGetNodeVersionByRangeOrLTS(t *testing.T) {
+func TestResolveNodeVersionRanges(t *testing.T) {
 	for k := range tests {
 		input := k
 		expected := tests[k]
 		t.Run(input, func(t *testing.T) {
-			actual, err := nodeman.GetNodeVersionByRangeOrLTS(input, &clientMock{})
+			actual, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, input, nodeman.NewDist(&clientMock{}))
 			assert.Nil(t, err)
 			assert.Equal(t, expected, actual)
 		})
 	}
+	actual, err := nodeman.ResolveNodeVersion(nodeman.PolicyCurrent, "15.x", nodeman.NewDist(&clientMock{}))
+	assert.Nil(t, err)
+	assert.Equal(t, "15.0.1", actual)
+}
+
+func TestResolveNodeVersionRangeErrors(t *testing.T) {
+	_, err := nodeman.ResolveNodeVersion(nodeman.PolicyLTS, "not a range", nodeman.NewDist(&clientMock{}))
+	assert.ErrorContains(t, err, "Error parsing node version range")
+
+	_, err = nodeman.ResolveNodeVersion(nodeman.PolicyCurrent, ">=99", nodeman.NewDist(&clientMock{}))
+	assert.ErrorContains(t, err, "No node release matches")
 }
@@ -0,0 +1,322 @@
+// Package npmrange implements the version range syntax used by npm,
+// as used in `engines.node`, dependency specs & dist-tag resolution
+package npmrange
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// Range a parsed npm version range, a union of comparator sets
+type Range struct {
+	raw  string
+	sets [][]comparator
+}
+
+type comparator struct {
+	op      string
+	version semver.Version
+}
+
+// partial a possibly incomplete version like 1, 1.2, 1.x or 1.2.3-beta.1
+type partial struct {
+	major, minor, patch int
+	pre                 []semver.PRVersion
+}
+
+const wildcard = -1
+
+var (
+	hyphenPattern   = regexp.MustCompile(`^\s*(\S+)\s+-\s+(\S+)\s*$`)
+	operatorSpacing = regexp.MustCompile(`(~>?|\^|[<>]=?|=)\s+`)
+	operatorPattern = regexp.MustCompile(`^(~>?|\^|[<>]=?|=)?(.*)$`)
+)
+
+// Parse parses an npm range like `^18.0.0`, `~16.14`, `18.x`, `>=14 || >=16` or `14 - 18`
+func Parse(value string) (Range, error) {
+	r := Range{raw: value}
+	for _, set := range strings.Split(value, "||") {
+		comparators, err := parseSet(set)
+		if err != nil {
+			return Range{}, fmt.Errorf("Invalid range %q: %w", value, err)
+		}
+		r.sets = append(r.sets, comparators)
+	}
+	return r, nil
+}
+
+// String returns the range as it was written
+func (r Range) String() string {
+	return r.raw
+}
+
+// Contains reports whether the version satisfies the range,
+// prerelease versions only satisfy comparator sets that name a prerelease of the same version
+func (r Range) Contains(version semver.Version) bool {
+	for _, set := range r.sets {
+		if setContains(set, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsString is like Contains for a version string, invalid versions are never contained
+func (r Range) ContainsString(version string) bool {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false
+	}
+	return r.Contains(v)
+}
+
+// MaxSatisfying returns the highest of the versions in the range, and false if none are
+func (r Range) MaxSatisfying(versions []semver.Version) (semver.Version, bool) {
+	var result semver.Version
+	found := false
+	for _, version := range versions {
+		if r.Contains(version) && (!found || version.GT(result)) {
+			result = version
+			found = true
+		}
+	}
+	return result, found
+}
+
+func setContains(set []comparator, version semver.Version) bool {
+	for _, c := range set {
+		if !c.matches(version) {
+			return false
+		}
+	}
+	if len(version.Pre) == 0 {
+		return true
+	}
+	for _, c := range set {
+		if len(c.version.Pre) > 0 && c.version.Major == version.Major &&
+			c.version.Minor == version.Minor && c.version.Patch == version.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) matches(version semver.Version) bool {
+	switch c.op {
+	case "<":
+		return version.LT(c.version)
+	case "<=":
+		return version.LTE(c.version)
+	case ">":
+		return version.GT(c.version)
+	case ">=":
+		return version.GTE(c.version)
+	}
+	return version.EQ(c.version)
+}
+
+func parseSet(set string) ([]comparator, error) {
+	if match := hyphenPattern.FindStringSubmatch(set); match != nil {
+		return parseHyphen(match[1], match[2])
+	}
+	set = operatorSpacing.ReplaceAllString(strings.TrimSpace(set), "$1")
+	fields := strings.Fields(set)
+	if len(fields) == 0 {
+		return []comparator{{op: ">=", version: semver.Version{}}}, nil
+	}
+	result := []comparator{}
+	for _, field := range fields {
+		comparators, err := parseComparator(field)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, comparators...)
+	}
+	return result, nil
+}
+
+func parseHyphen(from string, to string) ([]comparator, error) {
+	lower, err := parsePartial(from)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parsePartial(to)
+	if err != nil {
+		return nil, err
+	}
+	result := []comparator{{op: ">=", version: lower.floor()}}
+	switch {
+	case upper.major == wildcard:
+	case upper.minor == wildcard:
+		result = append(result, comparator{op: "<", version: upper.bump(0)})
+	case upper.patch == wildcard:
+		result = append(result, comparator{op: "<", version: upper.bump(1)})
+	default:
+		result = append(result, comparator{op: "<=", version: upper.floor()})
+	}
+	return result, nil
+}
+
+func parseComparator(value string) ([]comparator, error) {
+	match := operatorPattern.FindStringSubmatch(value)
+	op, rest := match[1], match[2]
+	p, err := parsePartial(rest)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "~", "~>":
+		return tilde(p), nil
+	case "^":
+		return caret(p), nil
+	case "", "=":
+		return xRange(p), nil
+	}
+	return primitive(op, p), nil
+}
+
+func tilde(p partial) []comparator {
+	switch {
+	case p.major == wildcard:
+		return xRange(p)
+	case p.minor == wildcard:
+		return between(p.floor(), p.bump(0))
+	}
+	return between(p.floor(), p.bump(1))
+}
+
+func caret(p partial) []comparator {
+	switch {
+	case p.major == wildcard:
+		return xRange(p)
+	case p.major != 0 || p.minor == wildcard:
+		return between(p.floor(), p.bump(0))
+	case p.minor != 0 || p.patch == wildcard:
+		return between(p.floor(), p.bump(1))
+	}
+	return between(p.floor(), p.bump(2))
+}
+
+func xRange(p partial) []comparator {
+	switch {
+	case p.major == wildcard:
+		return []comparator{{op: ">=", version: semver.Version{}}}
+	case p.minor == wildcard:
+		return between(p.floor(), p.bump(0))
+	case p.patch == wildcard:
+		return between(p.floor(), p.bump(1))
+	}
+	return []comparator{{op: "=", version: p.floor()}}
+}
+
+func primitive(op string, p partial) []comparator {
+	if p.major == wildcard {
+		if op == "<" || op == ">" {
+			// Nothing is below or above every version
+			return []comparator{{op: "<", version: lowestPrerelease(semver.Version{})}}
+		}
+		return []comparator{{op: ">=", version: semver.Version{}}}
+	}
+	position := 2
+	if p.minor == wildcard {
+		position = 0
+	} else if p.patch == wildcard {
+		position = 1
+	}
+	if position == 2 {
+		return []comparator{{op: op, version: p.floor()}}
+	}
+	switch op {
+	case ">":
+		return []comparator{{op: ">=", version: p.bump(position)}}
+	case "<=":
+		return []comparator{{op: "<", version: p.bump(position)}}
+	case "<":
+		return []comparator{{op: "<", version: lowestPrerelease(p.floor())}}
+	}
+	return []comparator{{op: ">=", version: p.floor()}}
+}
+
+func between(lower semver.Version, upper semver.Version) []comparator {
+	return []comparator{{op: ">=", version: lower}, {op: "<", version: upper}}
+}
+
+func parsePartial(value string) (partial, error) {
+	value = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(value), "="), "v")
+	if i := strings.Index(value, "+"); i >= 0 {
+		value = value[:i]
+	}
+	p := partial{major: wildcard, minor: wildcard, patch: wildcard}
+	numbers := value
+	if i := strings.Index(value, "-"); i >= 0 {
+		numbers = value[:i]
+		for _, part := range strings.Split(value[i+1:], ".") {
+			pre, err := semver.NewPRVersion(part)
+			if err != nil {
+				return p, err
+			}
+			p.pre = append(p.pre, pre)
+		}
+	}
+	parts := strings.Split(numbers, ".")
+	if len(parts) > 3 {
+		return p, fmt.Errorf("invalid version %q", value)
+	}
+	fields := []*int{&p.major, &p.minor, &p.patch}
+	matchedWildcard := false
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" || (part == "" && len(parts) == 1) {
+			matchedWildcard = true
+			continue
+		}
+		if matchedWildcard {
+			return p, fmt.Errorf("invalid version %q", value)
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return p, fmt.Errorf("invalid version %q", value)
+		}
+		*fields[i] = number
+	}
+	return p, nil
+}
+
+// floor the lowest version matching the partial
+func (p partial) floor() semver.Version {
+	v := semver.Version{Pre: p.pre}
+	if p.major != wildcard {
+		v.Major = uint64(p.major)
+	}
+	if p.minor != wildcard {
+		v.Minor = uint64(p.minor)
+	}
+	if p.patch != wildcard {
+		v.Patch = uint64(p.patch)
+	}
+	return v
+}
+
+// bump increments the major (0), minor (1) or patch (2) part, returning the lowest prerelease of that version
+func (p partial) bump(position int) semver.Version {
+	v := p.floor()
+	v.Pre = nil
+	switch position {
+	case 0:
+		v = semver.Version{Major: v.Major + 1}
+	case 1:
+		v = semver.Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		v.Patch++
+	}
+	return lowestPrerelease(v)
+}
+
+// lowestPrerelease returns the -0 prerelease, which sorts before every other release of the version
+func lowestPrerelease(v semver.Version) semver.Version {
+	v.Pre = []semver.PRVersion{{VersionNum: 0, IsNum: true}}
+	return v
+}
@@ -0,0 +1,96 @@
+package npmrange_test
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/rdaniels6813/cli-manager/internal/npmrange"
+	"github.com/stretchr/testify/assert"
+)
+
+var contains = []struct {
+	rng      string
+	version  string
+	expected bool
+}{
+	{"^18.0.0", "18.14.0", true},
+	{"^18.0.0", "19.0.0", false},
+	{"^18.0.0", "17.9.9", false},
+	{"^0.2.3", "0.2.9", true},
+	{"^0.2.3", "0.3.0", false},
+	{"^0.0.3", "0.0.4", false},
+	{"^0.x", "0.9.0", true},
+	{"~16.14", "16.14.9", true},
+	{"~16.14", "16.15.0", false},
+	{"~16", "16.99.0", true},
+	{"~1.2.3", "1.2.2", false},
+	{"18.x", "18.0.0", true},
+	{"18.x", "20.0.0", false},
+	{"18", "18.3.1", true},
+	{"18.2.*", "18.2.4", true},
+	{"*", "0.0.1", true},
+	{"", "14.0.0", true},
+	{">=14 || >=16", "14.1.0", true},
+	{">=14 || >=16", "12.22.0", false},
+	{"^14.17.0 || ^16.13.0 || >=18.0.0", "15.0.0", false},
+	{"^14.17.0 || ^16.13.0 || >=18.0.0", "16.20.0", true},
+	{"14 - 18", "18.9.0", true},
+	{"14 - 18", "19.0.0", false},
+	{"14.2 - 16.3.1", "14.1.9", false},
+	{"14.2 - 16.3.1", "16.3.1", true},
+	{"1.2.3 - 2.3", "2.3.9", true},
+	{"1.2.3 - 2.3", "2.4.0", false},
+	{">= 10.x < 14.x", "12.19.0", true},
+	{">=10.x <14.x", "14.0.0", false},
+	{">1.2", "1.2.9", false},
+	{">1.2", "1.3.0", true},
+	{"<=1.2", "1.2.9", true},
+	{"<1.2", "1.1.9", true},
+	{"<1.2", "1.2.0", false},
+	{"=v1.2.3", "1.2.3", true},
+	{"^18.0.0", "18.1.0-rc.1", false},
+	{"^18.1.0-rc.0", "18.1.0-rc.1", true},
+	{"^18.1.0-rc.0", "18.2.0-rc.1", false},
+	{"^18.1.0-rc.0", "18.2.0", true},
+	{">=1.2.3-alpha <1.2.3", "1.2.3-beta", true},
+}
+
+func TestContains(t *testing.T) {
+	for _, test := range contains {
+		test := test
+		t.Run(test.rng+" "+test.version, func(t *testing.T) {
+			r, err := npmrange.Parse(test.rng)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, r.ContainsString(test.version))
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, value := range []string{"latest", "1.2.3.4", "^abc", ">=1.x.y"} {
+		_, err := npmrange.Parse(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestMaxSatisfying(t *testing.T) {
+	versions := []semver.Version{
+		semver.MustParse("16.19.1"),
+		semver.MustParse("18.14.0"),
+		semver.MustParse("18.15.0-rc.1"),
+		semver.MustParse("19.6.0"),
+	}
+
+	max, ok := mustParse(t, "^18").MaxSatisfying(versions)
+	assert.True(t, ok)
+	assert.Equal(t, "18.14.0", max.String())
+
+	_, ok = mustParse(t, "^20").MaxSatisfying(versions)
+	assert.False(t, ok)
+}
+
+func mustParse(t *testing.T, value string) npmrange.Range {
+	r, err := npmrange.Parse(value)
+	assert.Nil(t, err)
+	return r
+}
@@ -0,0 +1,108 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	barWidth       = 30
+	renderInterval = 100 * time.Millisecond
+)
+
+// IsTerminal reports whether the file is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// Bar renders a progress bar with transferred bytes and ETA, it is written to as data is copied
+// Use NewBar to create an instance of this object
+type Bar struct {
+	out        io.Writer
+	label      string
+	total      int64
+	current    int64
+	start      int64
+	startTime  time.Time
+	lastRender time.Time
+}
+
+// NewBar creates a bar for a transfer of total bytes, of which offset bytes were already transferred.
+// A total <= 0 means the size is unknown.
+func NewBar(out io.Writer, label string, total int64, offset int64) *Bar {
+	return &Bar{
+		out:       out,
+		label:     label,
+		total:     total,
+		current:   offset,
+		start:     offset,
+		startTime: time.Now(),
+	}
+}
+
+// Write records len(p) transferred bytes
+func (b *Bar) Write(p []byte) (int, error) {
+	b.current += int64(len(p))
+	if now := time.Now(); now.Sub(b.lastRender) >= renderInterval {
+		b.lastRender = now
+		b.render()
+	}
+	return len(p), nil
+}
+
+// Finish renders the final state of the bar and ends the line
+func (b *Bar) Finish() {
+	b.render()
+	fmt.Fprintln(b.out)
+}
+
+func (b *Bar) render() {
+	fmt.Fprintf(b.out, "\r%s", b.String())
+}
+
+// String formats the current state of the bar
+func (b *Bar) String() string {
+	if b.total <= 0 {
+		return fmt.Sprintf("%s %s", b.label, FormatBytes(b.current))
+	}
+	filled := int(float64(barWidth) * float64(b.current) / float64(b.total))
+	if filled > barWidth {
+		filled = barWidth
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+	return fmt.Sprintf("%s [%s] %s / %s ETA %s",
+		b.label, bar, FormatBytes(b.current), FormatBytes(b.total), b.eta())
+}
+
+func (b *Bar) eta() string {
+	transferred := b.current - b.start
+	elapsed := time.Since(b.startTime)
+	if transferred <= 0 || elapsed <= 0 {
+		return "--:--"
+	}
+	remaining := time.Duration(float64(b.total-b.current) / float64(transferred) * float64(elapsed))
+	if remaining < 0 {
+		remaining = 0
+	}
+	remaining = remaining.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+}
+
+// FormatBytes formats a byte count using binary units
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
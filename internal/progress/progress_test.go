@@ -0,0 +1,39 @@
+package progress_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", progress.FormatBytes(512))
+	assert.Equal(t, "1.5 KiB", progress.FormatBytes(1536))
+	assert.Equal(t, "30.0 MiB", progress.FormatBytes(30*1024*1024))
+}
+
+func TestBarTracksResumedOffset(t *testing.T) {
+	var out bytes.Buffer
+	bar := progress.NewBar(&out, "node.tar.xz", 2048, 1024)
+
+	_, err := bar.Write(make([]byte, 1024))
+	bar.Finish()
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(out.String(), "\n"))
+	assert.Contains(t, bar.String(), "["+strings.Repeat("=", 30)+"]")
+	assert.Contains(t, bar.String(), "2.0 KiB / 2.0 KiB ETA 00:00")
+}
+
+func TestBarUnknownSize(t *testing.T) {
+	var out bytes.Buffer
+	bar := progress.NewBar(&out, "node.tar.xz", -1, 0)
+
+	_, err := bar.Write(make([]byte, 100))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "node.tar.xz 100 B", bar.String())
+}
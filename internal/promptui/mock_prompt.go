@@ -62,3 +62,18 @@ func (mr *MockPrompterMockRecorder) PromptPassword(message interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromptPassword", reflect.TypeOf((*MockPrompter)(nil).PromptPassword), message)
 }
+
+// PromptConfirm mocks base method
+func (m *MockPrompter) PromptConfirm(message string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromptConfirm", message)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromptConfirm indicates an expected call of PromptConfirm
+func (mr *MockPrompterMockRecorder) PromptConfirm(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromptConfirm", reflect.TypeOf((*MockPrompter)(nil).PromptConfirm), message)
+}
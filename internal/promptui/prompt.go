@@ -1,6 +1,8 @@
 package promptui
 
 import (
+	"errors"
+
 	"github.com/manifoldco/promptui"
 )
 
@@ -8,6 +10,7 @@ import (
 type Prompter interface {
 	PromptString(message string) (string, error)
 	PromptPassword(message string) (string, error)
+	PromptConfirm(message string) (bool, error)
 }
 
 // CLIPrompter prompts the user for input from the CLI
@@ -29,3 +32,16 @@ func (c *CLIPrompter) PromptPassword(message string) (string, error) {
 	}
 	return prompt.Run()
 }
+
+// PromptConfirm asks the user a yes/no question, answering no is not an error
+func (c *CLIPrompter) PromptConfirm(message string) (bool, error) {
+	prompt := promptui.Prompt{
+		Label:     message,
+		IsConfirm: true,
+	}
+	_, err := prompt.Run()
+	if errors.Is(err, promptui.ErrAbort) {
+		return false, nil
+	}
+	return err == nil, err
+}
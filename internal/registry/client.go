@@ -0,0 +1,140 @@
+// Package registry is a client for the npm registry, reading packuments the way npm install does
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// abbreviatedAccept asks for the abbreviated metadata npm install uses, falling back to the full packument
+const abbreviatedAccept = "application/vnd.npm.install-v1+json; q=1.0, application/json; q=0.8, */*"
+
+// ErrNotFound returned when the registry does not have a package
+var ErrNotFound = errors.New("not found")
+
+// HTTPClient sends the registry requests
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Packument the metadata of every published version of a package
+type Packument struct {
+	Name     string              `json:"name"`
+	DistTags map[string]string   `json:"dist-tags"`
+	Versions map[string]*Version `json:"versions"`
+}
+
+// Version the metadata of a published version
+type Version struct {
+	Name       string      `json:"name"`
+	Version    string      `json:"version"`
+	Engines    Engines     `json:"engines"`
+	Bin        interface{} `json:"bin"`
+	Dist       Dist        `json:"dist"`
+	Deprecated string      `json:"deprecated"`
+}
+
+// Dist where the tarball of a version is served from
+type Dist struct {
+	Tarball   string `json:"tarball"`
+	Integrity string `json:"integrity"`
+	Shasum    string `json:"shasum"`
+}
+
+// Engines the runtimes a version supports, some old packages list them as an array which is ignored
+type Engines map[string]string
+
+// UnmarshalJSON ignores engines that are not an object of strings
+func (e *Engines) UnmarshalJSON(data []byte) error {
+	var engines map[string]string
+	if json.Unmarshal(data, &engines) == nil {
+		*e = engines
+	}
+	return nil
+}
+
+// Client reads packuments from the configured registries, each at most once
+type Client struct {
+	config     *Config
+	client     HTTPClient
+	packuments map[string]*Packument
+}
+
+// NewClient creates a client for the registries & credentials in the config
+func NewClient(client HTTPClient, config *Config) *Client {
+	return &Client{config: config, client: client, packuments: map[string]*Packument{}}
+}
+
+// Packument fetches the abbreviated packument of the package, from registry when given
+// or else from the registry configured for the package
+func (c *Client) Packument(name string, registry string) (*Packument, error) {
+	if registry == "" {
+		registry = c.config.RegistryFor(name)
+	}
+	packumentURL := normalizeRegistry(registry) + strings.Replace(name, "/", "%2f", 1)
+	if packument, ok := c.packuments[packumentURL]; ok {
+		return packument, nil
+	}
+	req, err := http.NewRequest("GET", packumentURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", abbreviatedAccept)
+	if authorization := c.config.Authorization(packumentURL); authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%s was %w in %s", name, ErrNotFound, registry)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("Not authorized to read %s from %s: %s", name, registry, resp.Status)
+	default:
+		return nil, fmt.Errorf("Failed to get %s from %s: %s", name, registry, resp.Status)
+	}
+	var packument Packument
+	err = json.NewDecoder(resp.Body).Decode(&packument)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the packument of %s: %w", name, err)
+	}
+	c.packuments[packumentURL] = &packument
+	return &packument, nil
+}
+
+// Resolve returns the version a package spec like name@^1.2 or name@next installs
+func (c *Client) Resolve(spec string, registry string) (*Packument, *Version, error) {
+	name, selector, ok := ParseSpec(spec)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a registry package", spec)
+	}
+	packument, err := c.Packument(name, registry)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolved, err := ResolveVersion(name, selector, packument.DistTags, packument.VersionList())
+	if err != nil {
+		return nil, nil, err
+	}
+	version, ok := packument.Versions[resolved]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s@%s is missing from the packument", name, resolved)
+	}
+	return packument, version, nil
+}
+
+// VersionList returns the published versions
+func (p *Packument) VersionList() []string {
+	versions := make([]string, 0, len(p.Versions))
+	for version := range p.Versions {
+		versions = append(versions, version)
+	}
+	return versions
+}
@@ -0,0 +1,107 @@
+package registry_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+// registryServer a stand-in registry serving abbreviated packuments
+type registryServer struct {
+	*httptest.Server
+	token    string
+	requests int
+}
+
+func newRegistryServer(t *testing.T, token string) *registryServer {
+	s := &registryServer{token: token}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests++
+		assert.True(t, strings.HasPrefix(r.Header.Get("Accept"), "application/vnd.npm.install-v1+json"))
+		if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/typescript":
+			w.Header().Set("Content-Type", "application/vnd.npm.install-v1+json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"name":      "typescript",
+				"dist-tags": map[string]string{"latest": "5.0.2", "next": "5.1.0-dev.20230401"},
+				"versions": map[string]interface{}{
+					"4.9.5": map[string]interface{}{"name": "typescript", "version": "4.9.5",
+						"engines": map[string]string{"node": ">=4.2.0"},
+						"bin":     map[string]string{"tsc": "bin/tsc", "tsserver": "bin/tsserver"},
+						"dist":    map[string]string{"tarball": s.URL + "/typescript/-/typescript-4.9.5.tgz", "integrity": "sha512-a"}},
+					"5.0.2": map[string]interface{}{"name": "typescript", "version": "5.0.2",
+						"engines": map[string]string{"node": ">=12.20"},
+						"dist":    map[string]string{"tarball": s.URL + "/typescript/-/typescript-5.0.2.tgz", "integrity": "sha512-b"}},
+					"5.1.0-dev.20230401": map[string]interface{}{"name": "typescript", "version": "5.1.0-dev.20230401",
+						"engines": []string{"node >= 0.8"}, "deprecated": "use a newer nightly"},
+				},
+			})
+		case "/@corp%2fcli":
+			w.Write([]byte(`{"name":"@corp/cli","dist-tags":{"latest":"1.0.0"},"versions":{"1.0.0":{"name":"@corp/cli","version":"1.0.0"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestResolve(t *testing.T) {
+	server := newRegistryServer(t, "")
+	config := registry.NewConfig()
+	config.Registry = server.URL
+	client := registry.NewClient(http.DefaultClient, config)
+
+	packument, version, err := client.Resolve("typescript@^4", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "typescript", packument.Name)
+	assert.Equal(t, "4.9.5", version.Version)
+	assert.Equal(t, registry.Engines{"node": ">=4.2.0"}, version.Engines)
+	assert.Equal(t, "sha512-a", version.Dist.Integrity)
+
+	_, version, err = client.Resolve("typescript@next", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "use a newer nightly", version.Deprecated)
+	assert.Nil(t, version.Engines)
+	assert.Equal(t, 1, server.requests)
+
+	_, _, err = client.Resolve("typescript@3", "")
+	assert.ErrorContains(t, err, "No version of typescript matches 3")
+	_, _, err = client.Resolve("left-pad", "")
+	assert.ErrorContains(t, err, "left-pad was not found")
+	_, _, err = client.Resolve("rdaniels6813/cli-manager", "")
+	assert.ErrorContains(t, err, "not a registry package")
+}
+
+func TestResolveScopedWithToken(t *testing.T) {
+	server := newRegistryServer(t, "corp-token")
+	config := registry.NewConfig()
+	config.Scopes["@corp"] = server.URL + "/"
+
+	_, _, err := registry.NewClient(http.DefaultClient, config).Resolve("@corp/cli", "")
+	assert.ErrorContains(t, err, "Not authorized to read @corp/cli")
+
+	config.SetCredentials(server.URL, "corp-token")
+	_, version, err := registry.NewClient(http.DefaultClient, config).Resolve("@corp/cli", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.0", version.Version)
+}
+
+func TestResolveFromRegistryOverride(t *testing.T) {
+	server := newRegistryServer(t, "")
+	client := registry.NewClient(http.DefaultClient, registry.NewConfig())
+
+	_, version, err := client.Resolve("typescript", server.URL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "5.0.2", version.Version)
+}
@@ -0,0 +1,56 @@
+package registry
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// GitHubPackagesRegistry the npm registry of GitHub Packages, where scopes are GitHub users & organizations
+var GitHubPackagesRegistry = "https://npm.pkg.github.com/"
+
+// GitHubPackagesScope the OAuth scope a GitHub token needs to install from GitHub Packages
+const GitHubPackagesScope = "read:packages"
+
+// IsGitHubPackages reports whether the registry is GitHub Packages
+func IsGitHubPackages(registryURL string) bool {
+	parsed, err := url.Parse(registryURL)
+	github, _ := url.Parse(GitHubPackagesRegistry)
+	return err == nil && parsed.Host == github.Host
+}
+
+// GitHubPackagesToken returns a GitHub token when the scoped package is installed from GitHub Packages
+// but has no credentials for it. When registryURL or the registry configured for it is GitHub Packages the
+// token comes from getToken, which may ask the user to sign in. Otherwise GitHub Packages is only tried when
+// savedToken returns a token, nothing is configured for the scope and the default registry does not have the
+// package, so a mistyped name never starts a sign in. An empty token means the package is not from GitHub Packages.
+func (c *Client) GitHubPackagesToken(name string, registryURL string, savedToken func() (string, error),
+	getToken func() (string, error)) (string, error) {
+	if !strings.HasPrefix(name, "@") {
+		return "", nil
+	}
+	if registryURL == "" {
+		registryURL = c.config.RegistryFor(name)
+	}
+	if IsGitHubPackages(registryURL) {
+		if c.config.Authorization(normalizeRegistry(registryURL)+name) != "" {
+			return "", nil
+		}
+		return getToken()
+	}
+	if _, ok := c.config.Scopes[strings.SplitN(name, "/", 2)[0]]; ok || normalizeRegistry(registryURL) != c.config.Registry {
+		return "", nil
+	}
+	token, err := savedToken()
+	if err != nil || token == "" {
+		return "", err
+	}
+	if _, err := c.Packument(name, registryURL); !errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	c.config.SetCredentials(GitHubPackagesRegistry, token)
+	if _, err := c.Packument(name, GitHubPackagesRegistry); err != nil {
+		return "", nil
+	}
+	return token, nil
+}
@@ -0,0 +1,109 @@
+package registry_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGitHubPackages(t *testing.T) (*registry.Config, *registry.Client) {
+	github := newRegistryServer(t, "gh-token")
+	public := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(public.Close)
+	previous := registry.GitHubPackagesRegistry
+	registry.GitHubPackagesRegistry = github.URL + "/"
+	t.Cleanup(func() { registry.GitHubPackagesRegistry = previous })
+	config := registry.NewConfig()
+	config.Registry = public.URL + "/"
+	return config, registry.NewClient(http.DefaultClient, config)
+}
+
+func tokenGetter(calls *int, token string) func() (string, error) {
+	return func() (string, error) {
+		*calls++
+		return token, nil
+	}
+}
+
+func TestGitHubPackagesTokenDetectsScope(t *testing.T) {
+	_, client := setupGitHubPackages(t)
+	saved, calls := 0, 0
+
+	token, err := client.GitHubPackagesToken("@corp/cli", "", tokenGetter(&saved, "gh-token"), tokenGetter(&calls, "new-token"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "gh-token", token)
+	assert.Equal(t, 1, saved)
+	assert.Equal(t, 0, calls)
+}
+
+func TestGitHubPackagesTokenNotPublished(t *testing.T) {
+	_, client := setupGitHubPackages(t)
+	saved, calls := 0, 0
+
+	token, err := client.GitHubPackagesToken("@corp/missing", "", tokenGetter(&saved, "gh-token"), tokenGetter(&calls, "new-token"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", token)
+	assert.Equal(t, 0, calls)
+}
+
+func TestGitHubPackagesTokenWithoutSavedToken(t *testing.T) {
+	_, client := setupGitHubPackages(t)
+	saved, calls := 0, 0
+
+	// a mistyped name is not found on the default registry, which must not start a sign in
+	token, err := client.GitHubPackagesToken("@angular/clii", "", tokenGetter(&saved, ""), tokenGetter(&calls, "new-token"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", token)
+	assert.Equal(t, 1, saved)
+	assert.Equal(t, 0, calls)
+}
+
+func TestGitHubPackagesTokenSkipped(t *testing.T) {
+	config, client := setupGitHubPackages(t)
+	config.Scopes["@tools"] = "https://tools.example.com/"
+	calls := 0
+
+	for _, test := range []struct{ name, registry string }{
+		{"typescript", ""},
+		{"@tools/cli", ""},
+		{"@corp/cli", "https://corp.example.com/"},
+	} {
+		token, err := client.GitHubPackagesToken(test.name, test.registry, tokenGetter(&calls, "gh-token"),
+			tokenGetter(&calls, "gh-token"))
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, "", token, test.name)
+	}
+	assert.Equal(t, 0, calls)
+}
+
+func TestGitHubPackagesTokenForConfiguredRegistry(t *testing.T) {
+	config, client := setupGitHubPackages(t)
+	config.Scopes["@corp"] = registry.GitHubPackagesRegistry
+	calls := 0
+
+	token, err := client.GitHubPackagesToken("@corp/cli", "", tokenGetter(&calls, ""), tokenGetter(&calls, "gh-token"))
+	assert.Nil(t, err)
+	assert.Equal(t, "gh-token", token)
+	declined := func() (string, error) { return "", errors.New("authorization declined") }
+	_, err = client.GitHubPackagesToken("@other/cli", registry.GitHubPackagesRegistry, tokenGetter(&calls, ""), declined)
+	assert.ErrorContains(t, err, "authorization declined")
+
+	config.SetCredentials(registry.GitHubPackagesRegistry, "gh-token")
+	token, err = client.GitHubPackagesToken("@corp/cli", "", tokenGetter(&calls, ""), tokenGetter(&calls, "gh-token"))
+	assert.Nil(t, err)
+	assert.Equal(t, "", token)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIsGitHubPackages(t *testing.T) {
+	assert.True(t, registry.IsGitHubPackages("https://npm.pkg.github.com"))
+	assert.True(t, registry.IsGitHubPackages("https://npm.pkg.github.com/corp"))
+	assert.False(t, registry.IsGitHubPackages(registry.DefaultRegistry))
+}
@@ -0,0 +1,181 @@
+package registry
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultRegistry the public npm registry
+const DefaultRegistry = "https://registry.npmjs.org/"
+
+var envPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// Config the registries & credentials npm is configured with
+type Config struct {
+	// Registry the registry for unscoped packages
+	Registry string
+	// Scopes registries for scoped packages, keyed by scope like @corp
+	Scopes map[string]string
+	// credentials keyed by the registry URL without its scheme, like //npm.example.com/path/
+	credentials map[string]*credentials
+}
+
+type credentials struct {
+	token    string
+	auth     string
+	username string
+	password string
+}
+
+// NewConfig returns a config for the public registry without any credentials
+func NewConfig() *Config {
+	return &Config{Registry: DefaultRegistry, Scopes: map[string]string{}, credentials: map[string]*credentials{}}
+}
+
+// LoadConfig reads the user's .npmrc, then the .npmrc of the current folder and finally npm_config_registry,
+// each overriding the previous
+func LoadConfig() (*Config, error) {
+	config := NewConfig()
+	userconfig, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range []string{userconfig, ".npmrc"} {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = config.Parse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %w", path, err)
+		}
+	}
+	if registry := os.Getenv("npm_config_registry"); registry != "" {
+		config.Registry = normalizeRegistry(registry)
+	}
+	return config, nil
+}
+
+// Parse reads npmrc settings into the config, expanding ${VAR} references to environment variables
+func (c *Config) Parse(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = envPattern.ReplaceAllStringFunc(strings.Trim(strings.TrimSpace(value), `"'`), func(ref string) string {
+			return os.Getenv(envPattern.FindStringSubmatch(ref)[1])
+		})
+		c.set(key, value)
+	}
+	return scanner.Err()
+}
+
+// SetCredentials sets the token sent to the registry
+func (c *Config) SetCredentials(registry string, token string) {
+	c.credentialsFor(registryKey(registry)).token = token
+}
+
+func (c *Config) set(key string, value string) {
+	switch {
+	case key == "registry":
+		c.Registry = normalizeRegistry(value)
+	case strings.HasPrefix(key, "@") && strings.HasSuffix(key, ":registry"):
+		c.Scopes[strings.TrimSuffix(key, ":registry")] = normalizeRegistry(value)
+	case strings.HasPrefix(key, "//"):
+		i := strings.LastIndex(key, ":")
+		if i < 0 {
+			return
+		}
+		creds := c.credentialsFor(key[:i])
+		switch key[i+1:] {
+		case "_authToken":
+			creds.token = value
+		case "_auth":
+			creds.auth = value
+		case "username":
+			creds.username = value
+		case "_password":
+			password, err := base64.StdEncoding.DecodeString(value)
+			if err == nil {
+				creds.password = string(password)
+			}
+		}
+	}
+}
+
+func (c *Config) credentialsFor(key string) *credentials {
+	if !strings.HasSuffix(key, "/") {
+		key += "/"
+	}
+	if c.credentials[key] == nil {
+		c.credentials[key] = &credentials{}
+	}
+	return c.credentials[key]
+}
+
+// RegistryFor returns the registry a package is fetched from
+func (c *Config) RegistryFor(name string) string {
+	if strings.HasPrefix(name, "@") {
+		if registry, ok := c.Scopes[strings.SplitN(name, "/", 2)[0]]; ok {
+			return registry
+		}
+	}
+	return c.Registry
+}
+
+// Authorization returns the Authorization header for a request to the URL, using the credentials
+// configured for the longest registry path the URL is under
+func (c *Config) Authorization(rawURL string) string {
+	key := registryKey(rawURL)
+	var match *credentials
+	matched := ""
+	for prefix, creds := range c.credentials {
+		if strings.HasPrefix(key, prefix) && len(prefix) > len(matched) {
+			match, matched = creds, prefix
+		}
+	}
+	switch {
+	case match == nil:
+		return ""
+	case match.token != "":
+		return "Bearer " + match.token
+	case match.auth != "":
+		return "Basic " + match.auth
+	case match.username != "":
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(match.username+":"+match.password))
+	}
+	return ""
+}
+
+// registryKey strips the scheme from a URL, the form npmrc uses to scope credentials
+func registryKey(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+	return "//" + parsed.Host + parsed.Path
+}
+
+func normalizeRegistry(registry string) string {
+	if !strings.HasSuffix(registry, "/") {
+		registry += "/"
+	}
+	return registry
+}
@@ -0,0 +1,51 @@
+package registry_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+const npmrc = `
+; comment
+# another comment
+registry=https://npm.example.com/npm
+@corp:registry = "https://corp.example.com/api/npm/"
+//corp.example.com/api/npm/:_authToken=${CORP_TOKEN}
+//npm.example.com/:username=ci
+//npm.example.com/:_password=c2VjcmV0
+//basic.example.com/:_auth=Y2k6c2VjcmV0
+`
+
+func TestParseNpmrc(t *testing.T) {
+	t.Setenv("CORP_TOKEN", "corp-token")
+	config := registry.NewConfig()
+
+	assert.Nil(t, config.Parse(strings.NewReader(npmrc)))
+
+	assert.Equal(t, "https://npm.example.com/npm/", config.RegistryFor("typescript"))
+	assert.Equal(t, "https://npm.example.com/npm/", config.RegistryFor("@angular/cli"))
+	assert.Equal(t, "https://corp.example.com/api/npm/", config.RegistryFor("@corp/cli"))
+	assert.Equal(t, "Bearer corp-token", config.Authorization("https://corp.example.com/api/npm/@corp%2fcli"))
+	assert.Equal(t, "Basic Y2k6c2VjcmV0", config.Authorization("https://npm.example.com/npm/typescript"))
+	assert.Equal(t, "Basic Y2k6c2VjcmV0", config.Authorization("https://basic.example.com/typescript"))
+	assert.Equal(t, "", config.Authorization("https://corp.example.com/other/@corp%2fcli"))
+	assert.Equal(t, "", config.Authorization(registry.DefaultRegistry+"typescript"))
+}
+
+func TestLoadConfig(t *testing.T) {
+	userconfig := filepath.Join(t.TempDir(), "npmrc")
+	assert.Nil(t, os.WriteFile(userconfig, []byte("@corp:registry=https://corp.example.com/\n"), 0600))
+	t.Setenv("NPM_CONFIG_USERCONFIG", userconfig)
+	t.Setenv("npm_config_registry", "https://mirror.example.com")
+
+	config, err := registry.LoadConfig()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://mirror.example.com/", config.RegistryFor("typescript"))
+	assert.Equal(t, "https://corp.example.com/", config.RegistryFor("@corp/cli"))
+}
@@ -0,0 +1,224 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rdaniels6813/cli-manager/internal/store"
+)
+
+// storeService the service registry tokens are kept under in the auth store
+const storeService = "cli-manager:npm"
+
+// Entry a registry added with cli-manager registry add, along with the scopes installed from it
+type Entry struct {
+	URL    string   `json:"url"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// Registries the registries cli-manager installs from, their tokens are kept in the auth store
+type Registries struct {
+	path  string
+	store store.Store
+	// GitHubToken returns the saved GitHub token, used for GitHub Packages unless a token was added for it,
+	// so the token is read where it is kept rather than copied
+	GitHubToken func() (string, error) `json:"-"`
+	Entries     []*Entry               `json:"registries"`
+}
+
+// LoadRegistries reads the registries file at path, which may not exist yet
+func LoadRegistries(path string, store store.Store) (*Registries, error) {
+	registries := &Registries{path: path, store: store, Entries: []*Entry{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return registries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, registries)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %w", path, err)
+	}
+	return registries, nil
+}
+
+// Add registers a registry for the scopes, moving any scope already mapped to another registry.
+// The token is only replaced when one is given.
+func (r *Registries) Add(registryURL string, scopes []string, token string) (*Entry, error) {
+	registryURL, err := validateRegistry(registryURL)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		if !strings.HasPrefix(scope, "@") || len(scope) == 1 || strings.Contains(scope, "/") {
+			return nil, fmt.Errorf("Invalid scope %q, scopes look like @corp", scope)
+		}
+	}
+	entry := r.Find(registryURL)
+	if entry == nil {
+		entry = &Entry{URL: registryURL}
+		r.Entries = append(r.Entries, entry)
+	}
+	for _, other := range r.Entries {
+		if other != entry {
+			other.Scopes = without(other.Scopes, scopes)
+		}
+	}
+	entry.Scopes = append(without(entry.Scopes, scopes), scopes...)
+	sort.Strings(entry.Scopes)
+	if token != "" {
+		err = r.store.Set(storeService, registryURL, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entry, r.save()
+}
+
+// Remove forgets a registry and its token
+func (r *Registries) Remove(registryURL string) error {
+	entry := r.Find(registryURL)
+	if entry == nil {
+		return fmt.Errorf("Registry %s was not added", registryURL)
+	}
+	entries := []*Entry{}
+	for _, other := range r.Entries {
+		if other != entry {
+			entries = append(entries, other)
+		}
+	}
+	r.Entries = entries
+	err := r.store.Set(storeService, entry.URL, "")
+	if err != nil {
+		return err
+	}
+	return r.save()
+}
+
+// Find returns the entry for a registry, or nil when it was not added
+func (r *Registries) Find(registryURL string) *Entry {
+	registryURL = normalizeRegistry(registryURL)
+	for _, entry := range r.Entries {
+		if entry.URL == registryURL {
+			return entry
+		}
+	}
+	return nil
+}
+
+// HasToken reports whether a token is stored for the registry
+func (r *Registries) HasToken(entry *Entry) bool {
+	token, err := r.token(entry)
+	return err == nil && token != ""
+}
+
+// token returns the token added for the registry, or the saved GitHub token for GitHub Packages
+func (r *Registries) token(entry *Entry) (string, error) {
+	token, err := r.store.Get(storeService, entry.URL)
+	if err != nil || token != "" || !IsGitHubPackages(entry.URL) || r.GitHubToken == nil {
+		return token, err
+	}
+	return r.GitHubToken()
+}
+
+// Apply adds the scopes & tokens of the registries to the config
+func (r *Registries) Apply(config *Config) error {
+	for _, entry := range r.Entries {
+		for _, scope := range entry.Scopes {
+			config.Scopes[scope] = entry.URL
+		}
+		token, err := r.token(entry)
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			config.SetCredentials(entry.URL, token)
+		}
+	}
+	return nil
+}
+
+// WriteNpmrc writes an npmrc for npm to use as its userconfig, holding the settings of the user's
+// own npmrc followed by the scopes & tokens of the registries. The user's npmrc is never modified,
+// and as the npmrc holds tokens it is only readable by the user.
+func (r *Registries) WriteNpmrc(path string, userconfig string) error {
+	var b strings.Builder
+	data, err := os.ReadFile(userconfig)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		b.Write(data)
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("; registries managed by cli-manager\n")
+	for _, entry := range r.Entries {
+		for _, scope := range entry.Scopes {
+			fmt.Fprintf(&b, "%s:registry=%s\n", scope, entry.URL)
+		}
+		token, err := r.token(entry)
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			fmt.Fprintf(&b, "%s:_authToken=%s\n", registryKey(entry.URL), token)
+		}
+	}
+	err = os.WriteFile(path, []byte(b.String()), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(path, 0600)
+}
+
+// UserConfigPath returns the path of the user's npmrc
+func UserConfigPath() (string, error) {
+	if path := os.Getenv("NPM_CONFIG_USERCONFIG"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".npmrc"), nil
+}
+
+func (r *Registries) save() error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0600)
+}
+
+func validateRegistry(registryURL string) (string, error) {
+	parsed, err := url.Parse(registryURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", fmt.Errorf("Invalid registry URL %q, expected an http or https URL", registryURL)
+	}
+	return normalizeRegistry(registryURL), nil
+}
+
+func without(values []string, remove []string) []string {
+	result := []string{}
+	for _, value := range values {
+		keep := true
+		for _, r := range remove {
+			if value == r {
+				keep = false
+			}
+		}
+		if keep {
+			result = append(result, value)
+		}
+	}
+	return result
+}
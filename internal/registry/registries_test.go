@@ -0,0 +1,105 @@
+package registry_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore an auth store kept in memory
+type memoryStore map[string]map[string]string
+
+func (s memoryStore) Get(hostname string, key string) (string, error) {
+	return s[hostname][key], nil
+}
+
+func (s memoryStore) Set(hostname string, key string, value string) error {
+	if s[hostname] == nil {
+		s[hostname] = map[string]string{}
+	}
+	s[hostname][key] = value
+	return nil
+}
+
+func TestAddRegistries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registries.json")
+	registries, err := registry.LoadRegistries(path, memoryStore{})
+	assert.Nil(t, err)
+
+	_, err = registries.Add("https://corp.example.com/npm", []string{"@corp", "@tools"}, "corp-token")
+	assert.Nil(t, err)
+	entry, err := registries.Add("https://tools.example.com/", []string{"@tools"}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, &registry.Entry{URL: "https://tools.example.com/", Scopes: []string{"@tools"}}, entry)
+	_, err = registries.Add("corp.example.com", nil, "")
+	assert.ErrorContains(t, err, "Invalid registry URL")
+	_, err = registries.Add("https://corp.example.com/npm", []string{"corp"}, "")
+	assert.ErrorContains(t, err, "Invalid scope")
+
+	config := registry.NewConfig()
+	assert.Nil(t, registries.Apply(config))
+	assert.Equal(t, "https://corp.example.com/npm/", config.RegistryFor("@corp/cli"))
+	assert.Equal(t, "https://tools.example.com/", config.RegistryFor("@tools/cli"))
+	assert.Equal(t, "Bearer corp-token", config.Authorization("https://corp.example.com/npm/@corp%2fcli"))
+	assert.Equal(t, "", config.Authorization("https://tools.example.com/@tools%2fcli"))
+}
+
+func TestGitHubPackagesUsesSavedGitHubToken(t *testing.T) {
+	store := memoryStore{}
+	registries, err := registry.LoadRegistries(filepath.Join(t.TempDir(), "registries.json"), store)
+	assert.Nil(t, err)
+	registries.GitHubToken = func() (string, error) { return "rotated-token", nil }
+
+	entry, err := registries.Add(registry.GitHubPackagesRegistry, []string{"@corp"}, "")
+	assert.Nil(t, err)
+
+	assert.Empty(t, store[registry.GitHubPackagesRegistry])
+	assert.True(t, registries.HasToken(entry))
+	config := registry.NewConfig()
+	assert.Nil(t, registries.Apply(config))
+	assert.Equal(t, "Bearer rotated-token", config.Authorization(registry.GitHubPackagesRegistry+"@corp%2fcli"))
+}
+
+func TestRemoveRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registries.json")
+	store := memoryStore{}
+	registries, err := registry.LoadRegistries(path, store)
+	assert.Nil(t, err)
+	entry, err := registries.Add("https://corp.example.com/", []string{"@corp"}, "corp-token")
+	assert.Nil(t, err)
+	assert.True(t, registries.HasToken(entry))
+
+	assert.Nil(t, registries.Remove("https://corp.example.com"))
+
+	assert.False(t, registries.HasToken(entry))
+	loaded, err := registry.LoadRegistries(path, store)
+	assert.Nil(t, err)
+	assert.Empty(t, loaded.Entries)
+	assert.ErrorContains(t, loaded.Remove("https://corp.example.com/"), "was not added")
+}
+
+func TestWriteNpmrc(t *testing.T) {
+	dir := t.TempDir()
+	userconfig := filepath.Join(dir, ".npmrc")
+	userNpmrc := "registry=https://mirror.example.com/\nfund=false"
+	assert.Nil(t, os.WriteFile(userconfig, []byte(userNpmrc), 0600))
+	registries, err := registry.LoadRegistries(filepath.Join(dir, "registries.json"), memoryStore{})
+	assert.Nil(t, err)
+	_, err = registries.Add("https://corp.example.com/npm/", []string{"@corp"}, "corp-token")
+	assert.Nil(t, err)
+	npmrc := filepath.Join(dir, "npmrc")
+
+	assert.Nil(t, registries.WriteNpmrc(npmrc, userconfig))
+
+	data, err := os.ReadFile(npmrc)
+	assert.Nil(t, err)
+	assert.Equal(t, userNpmrc+"\n; registries managed by cli-manager\n"+
+		"@corp:registry=https://corp.example.com/npm/\n//corp.example.com/npm/:_authToken=corp-token\n", string(data))
+	unchanged, err := os.ReadFile(userconfig)
+	assert.Nil(t, err)
+	assert.Equal(t, userNpmrc, string(unchanged))
+	assert.Nil(t, registries.WriteNpmrc(npmrc, filepath.Join(dir, "missing")))
+}
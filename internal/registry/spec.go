@@ -0,0 +1,59 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/rdaniels6813/cli-manager/internal/npmrange"
+)
+
+// LatestTag the dist-tag npm installs when a spec names no version
+const LatestTag = "latest"
+
+// ParseSpec splits an npm package spec like @scope/name@^1.2 into its name and version range or dist-tag,
+// ok is false for specs that do not come from a registry such as git repositories
+func ParseSpec(spec string) (name string, selector string, ok bool) {
+	if strings.Contains(spec, ":") || strings.Contains(spec, "#") {
+		return "", "", false
+	}
+	at := strings.LastIndex(spec, "@")
+	if at > 0 {
+		name, selector = spec[:at], spec[at+1:]
+	} else {
+		name = spec
+	}
+	if strings.Count(name, "/") > 1 || (strings.Contains(name, "/") && !strings.HasPrefix(name, "@")) {
+		return "", "", false
+	}
+	return name, selector, name != ""
+}
+
+// ResolveVersion returns the version a selector picks the way npm does: a dist-tag, the latest dist-tag
+// when it satisfies the range, or else the highest version in the range
+func ResolveVersion(name string, selector string, distTags map[string]string, versions []string) (string, error) {
+	if selector == "" {
+		selector = LatestTag
+	}
+	if version, ok := distTags[selector]; ok {
+		return version, nil
+	}
+	versionRange, err := npmrange.Parse(selector)
+	if err != nil {
+		return "", fmt.Errorf("%s is neither a dist-tag nor a version range of %s", selector, name)
+	}
+	if latest, err := semver.ParseTolerant(distTags[LatestTag]); err == nil && versionRange.Contains(latest) {
+		return latest.String(), nil
+	}
+	parsed := make([]semver.Version, 0, len(versions))
+	for _, version := range versions {
+		if v, err := semver.ParseTolerant(version); err == nil {
+			parsed = append(parsed, v)
+		}
+	}
+	wanted, found := versionRange.MaxSatisfying(parsed)
+	if !found {
+		return "", fmt.Errorf("No version of %s matches %s", name, selector)
+	}
+	return wanted.String(), nil
+}
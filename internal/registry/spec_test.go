@@ -0,0 +1,52 @@
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+var specTests = []struct {
+	spec     string
+	name     string
+	selector string
+	ok       bool
+}{
+	{"typescript", "typescript", "", true},
+	{"typescript@^4.9", "typescript", "^4.9", true},
+	{"@angular/cli", "@angular/cli", "", true},
+	{"@angular/cli@next", "@angular/cli", "next", true},
+	{"rdaniels6813/cli-manager", "", "", false},
+	{"rdaniels6813/cli-manager#main", "", "", false},
+	{"git+https://github.com/rdaniels6813/cli-manager.git", "", "", false},
+}
+
+func TestParseSpec(t *testing.T) {
+	for _, test := range specTests {
+		name, selector, ok := registry.ParseSpec(test.spec)
+		assert.Equal(t, test.ok, ok, test.spec)
+		assert.Equal(t, test.name, name, test.spec)
+		assert.Equal(t, test.selector, selector, test.spec)
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	versions := []string{"4.8.4", "4.9.4", "4.9.5", "5.0.0-beta", "5.0.2", "5.1.0-dev.20230401"}
+	distTags := map[string]string{"latest": "5.0.2", "next": "5.1.0-dev.20230401"}
+	for selector, expected := range map[string]string{
+		"":     "5.0.2",
+		"4":    "4.9.5",
+		"~4.8": "4.8.4",
+		">=4":  "5.0.2",
+		"next": "5.1.0-dev.20230401",
+	} {
+		wanted, err := registry.ResolveVersion("typescript", selector, distTags, versions)
+		assert.Nil(t, err, selector)
+		assert.Equal(t, expected, wanted, selector)
+	}
+	_, err := registry.ResolveVersion("typescript", "3", distTags, versions)
+	assert.ErrorContains(t, err, "No version of typescript matches 3")
+	_, err = registry.ResolveVersion("typescript", "beta", distTags, versions)
+	assert.ErrorContains(t, err, "neither a dist-tag nor a version range")
+}
@@ -69,6 +69,10 @@ func (f *FSStore) Set(hostname string, key string, value string) error {
 	if err != nil {
 		return err
 	}
+	err = cfg.Truncate(0)
+	if err != nil {
+		return err
+	}
 	e := json.NewEncoder(cfg)
 	return e.Encode(&values)
 }
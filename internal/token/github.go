@@ -35,6 +35,11 @@ func scopesToAccount(scopes []string) string {
 	return strings.Join(scopes, ";")
 }
 
+// GetSavedToken returns the token saved for the scopes without asking the user to sign in, empty when there is none
+func (t *OSTokenManager) GetSavedToken(scopes []string) (string, error) {
+	return t.store.Get(serviceName, scopesToAccount(scopes))
+}
+
 func (t *OSTokenManager) GetNewOrSavedToken(scopes []string) (string, error) {
 	token, err := t.store.Get(serviceName, scopesToAccount(scopes))
 	if token == "" || err != nil {
@@ -0,0 +1,10 @@
+package util
+
+import "regexp"
+
+var commandNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// IsCommandName reports whether the name is a plain command name, safe to use as a file name and in shell scripts
+func IsCommandName(name string) bool {
+	return name != "." && name != ".." && commandNamePattern.MatchString(name)
+}
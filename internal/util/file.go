@@ -0,0 +1,68 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// WriteFileAtomic replaces the file through a rename, so it is never left half written
+func WriteFileAtomic(aos afero.Fs, path string, data []byte, perm os.FileMode) error {
+	f, err := afero.TempFile(aos, filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = aos.Chmod(f.Name(), perm)
+	}
+	if err != nil {
+		aos.Remove(f.Name())
+		return err
+	}
+	return aos.Rename(f.Name(), path)
+}
+
+// RemoveSnippets removes every occurrence of the snippets from the file a symlink at path points to, leaving the
+// rest of it as it was, and reports whether the file changed. With deleteEmpty the file is deleted once nothing
+// is left in it.
+func RemoveSnippets(aos afero.Fs, path string, deleteEmpty bool, snippets ...string) (bool, error) {
+	path, err := filepath.EvalSymlinks(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	info, err := aos.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	data, err := afero.ReadFile(aos, path)
+	if err != nil {
+		return false, err
+	}
+	content := string(data)
+	for _, snippet := range snippets {
+		// the snippet goes with the newline written before it, unless that newline ended a line written after it
+		for i := strings.Index(content, snippet); i >= 0; i = strings.Index(content, snippet) {
+			rest := content[i+len(snippet):]
+			if i > 0 && content[i-1] != '\n' && rest != "" {
+				rest = "\n" + rest
+			}
+			content = content[:i] + rest
+		}
+	}
+	if content == string(data) {
+		return false, nil
+	}
+	if content == "" && deleteEmpty {
+		return true, aos.Remove(path)
+	}
+	return true, WriteFileAtomic(aos, path, []byte(content), info.Mode().Perm())
+}
@@ -0,0 +1,94 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rdaniels6813/cli-manager/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+const snippet = "\neval \"$(cli-manager completion -g -z)\"\n"
+
+func TestRemoveSnippets(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		expected string
+		removed  bool
+	}{
+		{"only the snippet", snippet, "", true},
+		{"appended", "export A=1\n" + snippet, "export A=1\n", true},
+		{"appended without trailing newline", "export A=1" + snippet, "export A=1", true},
+		{"in the middle", "export A=1\n" + snippet + "export B=2\n", "export A=1\nexport B=2\n", true},
+		{"in the middle of a file without trailing newlines", "export A=1" + snippet + "export B=2",
+			"export A=1\nexport B=2", true},
+		{"repeated", "export A=1\n" + snippet + snippet + "export B=2\n" + snippet, "export A=1\nexport B=2\n", true},
+		{"same line with other indentation", "export A=1\n  eval \"$(cli-manager completion -g -z)\"\n",
+			"export A=1\n  eval \"$(cli-manager completion -g -z)\"\n", false},
+		{"missing", "export A=1\n", "export A=1\n", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".zshrc")
+			assert.Nil(t, os.WriteFile(path, []byte(c.content), 0640))
+
+			removed, err := util.RemoveSnippets(afero.NewOsFs(), path, false, snippet)
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.removed, removed)
+			data, err := os.ReadFile(path)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, string(data))
+			info, err := os.Stat(path)
+			assert.Nil(t, err)
+			if runtime.GOOS != "windows" {
+				assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestRemoveSnippetsFollowsSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need extra privileges on windows")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "zshrc")
+	assert.Nil(t, os.MkdirAll(filepath.Dir(target), 0700))
+	assert.Nil(t, os.WriteFile(target, []byte("export A=1\n"+snippet), 0600))
+	link := filepath.Join(dir, ".zshrc")
+	assert.Nil(t, os.Symlink(target, link))
+
+	removed, err := util.RemoveSnippets(afero.NewOsFs(), link, false, snippet)
+
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	info, err := os.Lstat(link)
+	assert.Nil(t, err)
+	assert.Equal(t, os.ModeSymlink, info.Mode()&os.ModeSymlink)
+	data, err := os.ReadFile(target)
+	assert.Nil(t, err)
+	assert.Equal(t, "export A=1\n", string(data))
+}
+
+func TestRemoveSnippetsDeletesEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cli-manager-env.fish")
+	assert.Nil(t, os.WriteFile(path, []byte(snippet), 0600))
+
+	removed, err := util.RemoveSnippets(afero.NewOsFs(), path, true, snippet)
+
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	assert.NoFileExists(t, path)
+}
+
+func TestRemoveSnippetsMissingFile(t *testing.T) {
+	removed, err := util.RemoveSnippets(afero.NewOsFs(), filepath.Join(t.TempDir(), ".zshrc"), false, snippet)
+
+	assert.Nil(t, err)
+	assert.False(t, removed)
+}